@@ -14,6 +14,8 @@ type appState struct {
 	cache    *cache.Cache
 	logger   *debug.Logger
 	cfgPath  string
+	// loadConfig re-reads cfgPath with command-line overrides applied.
+	loadConfig func() (config.Config, error)
 }
 
 type exitCodeError struct {
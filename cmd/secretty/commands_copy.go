@@ -125,9 +125,24 @@ func ensureCopyAllowed(state *appState) error {
 	if state.cfg.Mode == types.ModeStrict && state.cfg.Strict.DisableCopyOriginal {
 		return errors.New("copy original is disabled in strict mode")
 	}
+	if state.cfg.LockedUntilExit() {
+		return errCopyLocked
+	}
 	return nil
 }
 
+var errCopyLocked = errors.New("copy is locked for this session by strict.lock_until_exit")
+
+func mapCopyIPCError(err error, unsupported string) error {
+	if errors.Is(err, ipc.ErrUnsupportedOperation) {
+		return errors.New(unsupported)
+	}
+	if errors.Is(err, ipc.ErrPolicyLocked) {
+		return errCopyLocked
+	}
+	return err
+}
+
 func runCopyLast(state *appState) error {
 	if err := ensureCopyAllowed(state); err != nil {
 		return err
@@ -154,10 +169,7 @@ func copyLast(state *appState) (copyResult, error) {
 	if socketPath := os.Getenv("SECRETTY_SOCKET"); socketPath != "" {
 		payload, resp, err := ipc.FetchLast(socketPath)
 		if err != nil {
-			if errors.Is(err, ipc.ErrUnsupportedOperation) {
-				return copyResult{}, errors.New("copy last requires a refreshed SecreTTY wrapper; restart your shell or run `secretty shell` again")
-			}
-			return copyResult{}, err
+			return copyResult{}, mapCopyIPCError(err, "copy last requires a refreshed SecreTTY wrapper; restart your shell or run `secretty shell` again")
 		}
 		if len(payload) == 0 {
 			return copyResult{}, errors.New("empty payload from copy cache")
@@ -190,10 +202,7 @@ func copyByID(state *appState, id int) (copyResult, error) {
 	if socketPath := os.Getenv("SECRETTY_SOCKET"); socketPath != "" {
 		payload, resp, err := ipc.FetchByID(socketPath, id)
 		if err != nil {
-			if errors.Is(err, ipc.ErrUnsupportedOperation) {
-				return copyResult{}, errors.New("copy pick requires a refreshed SecreTTY wrapper; restart your shell or run `secretty shell` again")
-			}
-			return copyResult{}, err
+			return copyResult{}, mapCopyIPCError(err, "copy pick requires a refreshed SecreTTY wrapper; restart your shell or run `secretty shell` again")
 		}
 		if len(payload) == 0 {
 			return copyResult{}, errors.New("empty payload from copy cache")
@@ -226,10 +235,7 @@ func listCachedSecrets(state *appState) ([]copyEntry, error) {
 	if socketPath := os.Getenv("SECRETTY_SOCKET"); socketPath != "" {
 		records, err := ipc.ListSecrets(socketPath)
 		if err != nil {
-			if errors.Is(err, ipc.ErrUnsupportedOperation) {
-				return nil, errors.New("copy pick requires a refreshed SecreTTY wrapper; restart your shell or run `secretty shell` again")
-			}
-			return nil, err
+			return nil, mapCopyIPCError(err, "copy pick requires a refreshed SecreTTY wrapper; restart your shell or run `secretty shell` again")
 		}
 		out := make([]copyEntry, 0, len(records))
 		for _, rec := range records {
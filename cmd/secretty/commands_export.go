@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/suryansh-23/secretty/internal/ipc"
+	"github.com/suryansh-23/secretty/internal/transcript"
+)
+
+func newExportCmd() *cobra.Command {
+	var (
+		lastLines int
+		lastBytes int
+		stripANSI bool
+		outPath   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write a sanitized transcript of the active wrapped session",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			socketPath := os.Getenv("SECRETTY_SOCKET")
+			if socketPath == "" {
+				return errors.New("export requires an active wrapped session; run inside `secretty shell`")
+			}
+			if err := validateExportFlags(lastLines, lastBytes); err != nil {
+				return err
+			}
+			data, err := ipc.Export(socketPath, lastLines, lastBytes)
+			if err != nil {
+				if errors.Is(err, ipc.ErrUnsupportedOperation) {
+					return errors.New("export requires a refreshed SecreTTY wrapper; restart your shell or run `secretty shell` again")
+				}
+				return err
+			}
+			if stripANSI {
+				data = transcript.StripANSI(data)
+			}
+			if outPath == "" || outPath == "-" {
+				_, err := cmd.OutOrStdout().Write(data)
+				return err
+			}
+			if err := os.WriteFile(outPath, data, 0o600); err != nil {
+				return fmt.Errorf("write transcript: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "Wrote %d bytes to %s\n", len(data), outPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&lastLines, "last", 0, "export the last N lines (default: entire transcript)")
+	cmd.Flags().IntVar(&lastBytes, "bytes", 0, "export the last N bytes")
+	cmd.Flags().BoolVar(&stripANSI, "strip-ansi", false, "remove escape sequences and normalize line endings")
+	cmd.Flags().StringVarP(&outPath, "output", "o", "", "write to file instead of stdout")
+	return cmd
+}
+
+func validateExportFlags(lastLines, lastBytes int) error {
+	if lastLines < 0 {
+		return errors.New("--last must be greater than zero")
+	}
+	if lastBytes < 0 {
+		return errors.New("--bytes must be greater than zero")
+	}
+	if lastLines > 0 && lastBytes > 0 {
+		return errors.New("use only one of: --last, --bytes")
+	}
+	return nil
+}
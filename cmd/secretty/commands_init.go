@@ -89,11 +89,7 @@ func newInitCmd(cfgPath *string) *cobra.Command {
 				),
 				huh.NewGroup(
 					huh.NewMultiSelect[string]().Title("Enable rulesets").Value(&selectedRulesets).Options(
-						huh.NewOption("Web3 (EVM keys)", "web3"),
-						huh.NewOption("API keys", "api_keys"),
-						huh.NewOption("Auth tokens (JWT/Bearer)", "auth_tokens"),
-						huh.NewOption("Cloud credentials", "cloud"),
-						huh.NewOption("Passwords", "passwords"),
+						rulesetOptions(cfg)...,
 					),
 				),
 				huh.NewGroup(
@@ -181,22 +177,25 @@ func newInitCmd(cfgPath *string) *cobra.Command {
 	return cmd
 }
 
+func rulesetOptions(cfg config.Config) []huh.Option[string] {
+	names := cfg.Rulesets.Names()
+	options := make([]huh.Option[string], 0, len(names))
+	for _, name := range names {
+		label := name
+		if desc := cfg.Rulesets[name].Description; desc != "" {
+			label = fmt.Sprintf("%s: %s", name, desc)
+		}
+		options = append(options, huh.NewOption(label, name))
+	}
+	return options
+}
+
 func defaultRulesetSelections(cfg config.Config) []string {
 	var out []string
-	if cfg.Rulesets.Web3.Enabled {
-		out = append(out, "web3")
-	}
-	if cfg.Rulesets.APIKeys.Enabled {
-		out = append(out, "api_keys")
-	}
-	if cfg.Rulesets.AuthTokens.Enabled {
-		out = append(out, "auth_tokens")
-	}
-	if cfg.Rulesets.Cloud.Enabled {
-		out = append(out, "cloud")
-	}
-	if cfg.Rulesets.Passwords.Enabled {
-		out = append(out, "passwords")
+	for _, name := range cfg.Rulesets.Names() {
+		if cfg.Rulesets[name].Enabled {
+			out = append(out, name)
+		}
 	}
 	return out
 }
@@ -206,11 +205,9 @@ func applyRulesetSelections(cfg *config.Config, selected []string) {
 	for _, name := range selected {
 		set[name] = true
 	}
-	cfg.Rulesets.Web3.Enabled = set["web3"]
-	cfg.Rulesets.APIKeys.Enabled = set["api_keys"]
-	cfg.Rulesets.AuthTokens.Enabled = set["auth_tokens"]
-	cfg.Rulesets.Cloud.Enabled = set["cloud"]
-	cfg.Rulesets.Passwords.Enabled = set["passwords"]
+	for _, name := range cfg.Rulesets.Names() {
+		cfg.Rulesets.SetEnabled(name, set[name])
+	}
 }
 
 func allowlistOptions() []huh.Option[string] {
@@ -290,7 +287,10 @@ func runSelfTest(cfg config.Config) error {
 		return err
 	}
 	line := []byte("PRIVATE_KEY=" + key)
-	detector := detect.NewEngine(cfg)
+	detector, err := detect.NewEngine(cfg)
+	if err != nil {
+		return err
+	}
 	matches := detector.Find(line)
 	redactor := redact.NewRedactor(cfg)
 	out, err := redactor.Apply(line, matches)
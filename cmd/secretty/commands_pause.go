@@ -120,6 +120,9 @@ func mapPauseIPCError(err error) error {
 	if errors.Is(err, ipc.ErrUnsupportedOperation) {
 		return errors.New("pause requires a refreshed SecreTTY wrapper; restart your shell or run `secretty shell` again")
 	}
+	if errors.Is(err, ipc.ErrPolicyLocked) {
+		return errors.New("pause is locked for this session by strict.lock_until_exit")
+	}
 	return err
 }
 
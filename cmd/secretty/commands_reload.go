@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/suryansh-23/secretty/internal/ipc"
+)
+
+func newReloadCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reload",
+		Short: "Apply config changes to the active wrapped session",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			socketPath := os.Getenv("SECRETTY_SOCKET")
+			if socketPath == "" {
+				return errors.New("reload requires an active wrapped session; run inside `secretty shell`")
+			}
+			if err := ipc.Reload(socketPath); err != nil {
+				switch {
+				case errors.Is(err, ipc.ErrUnsupportedOperation):
+					return errors.New("reload requires a refreshed SecreTTY wrapper; restart your shell or run `secretty shell` again")
+				case errors.Is(err, ipc.ErrPolicyLocked):
+					return errReloadLocked
+				}
+				return err
+			}
+			_, err := fmt.Fprintln(cmd.OutOrStdout(), "Config reloaded.")
+			return err
+		},
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/suryansh-23/secretty/internal/corpus"
+	"github.com/suryansh-23/secretty/internal/detect"
+)
+
+const previewContext = 24
+
+func newRulesCmd(state *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Inspect and test detection rules",
+	}
+	cmd.AddCommand(newRulesTestCmd(state))
+	cmd.AddCommand(newRulesVerifyCmd(state))
+	return cmd
+}
+
+func newRulesTestCmd(state *appState) *cobra.Command {
+	return &cobra.Command{
+		Use:   "test [file|-]",
+		Short: "Run the configured detectors over sample input and explain each match",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := readInput(cmd, args)
+			if err != nil {
+				return err
+			}
+			engine, err := detect.NewEngine(state.cfg)
+			if err != nil {
+				return err
+			}
+			return writeRulesReport(cmd.OutOrStdout(), data, engine.Explain(data))
+		},
+	}
+}
+
+func newRulesVerifyCmd(state *appState) *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify <dir>",
+		Short: "Check detectors against a corpus of annotated fixtures",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fixtures, err := corpus.Load(args[0])
+			if err != nil {
+				return err
+			}
+			report, err := corpus.Verify(fixtures, state.cfg)
+			if err != nil {
+				return err
+			}
+			if err := writeVerifyReport(cmd.OutOrStdout(), report); err != nil {
+				return err
+			}
+			if !report.OK() {
+				return fmt.Errorf("corpus verification failed: %d failure(s)", len(report.Failures))
+			}
+			return nil
+		},
+	}
+}
+
+func writeVerifyReport(w io.Writer, report corpus.Report) error {
+	var b strings.Builder
+	for _, failure := range report.Failures {
+		fmt.Fprintln(&b, failure)
+	}
+	if len(report.Failures) > 0 {
+		b.WriteString("\n")
+	}
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RULE\tEXPECTED\tDETECTED\tFALSE POS\tFALSE NEG")
+	for _, s := range report.Rules {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\n", s.Rule, s.Expected, s.Detected, s.FalsePositives, s.FalseNegatives)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(&b, "\n%d fixture(s), %d failure(s)\n", report.Fixtures, len(report.Failures))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// readInput reads the named file, or stdin when the argument is missing or "-".
+func readInput(cmd *cobra.Command, args []string) ([]byte, error) {
+	if len(args) == 0 || args[0] == "-" {
+		return io.ReadAll(cmd.InOrStdin())
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("read input: %w", err)
+	}
+	return data, nil
+}
+
+// writeRulesReport prints each finding without the matched bytes.
+func writeRulesReport(w io.Writer, data []byte, ex detect.Explanation) error {
+	spans := make([][2]int, 0, len(ex.Kept)+len(ex.Dropped))
+	for _, f := range ex.Kept {
+		spans = append(spans, [2]int{f.Start, f.End})
+	}
+	for _, f := range ex.Dropped {
+		spans = append(spans, [2]int{f.Start, f.End})
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d match(es), %d dropped candidate(s)\n", len(ex.Kept), len(ex.Dropped))
+	for i, f := range ex.Kept {
+		line, col := lineCol(data, f.Start)
+		fmt.Fprintf(&b, "\nmatch %d: %s\n", i+1, f.RuleName)
+		fmt.Fprintf(&b, "  type=%s severity=%s source=%s action=%s\n", f.SecretType, f.Severity, f.Source, f.Action)
+		if f.Encoding != "" {
+			fmt.Fprintf(&b, "  decoded=%s\n", f.Encoding)
+		}
+		fmt.Fprintf(&b, "  span=%d-%d (line %d, col %d) length=%d\n", f.Start, f.End, line, col, f.End-f.Start)
+		fmt.Fprintf(&b, "  context=%s\n", contextLabel(f))
+		fmt.Fprintf(&b, "  preview: %s\n", maskedPreview(data, f.Start, f.End, spans))
+	}
+	if len(ex.Dropped) > 0 {
+		b.WriteString("\ndropped:\n")
+		for _, f := range ex.Dropped {
+			line, col := lineCol(data, f.Start)
+			fmt.Fprintf(&b, "  %s span=%d-%d (line %d, col %d) context=%s: %s\n", f.RuleName, f.Start, f.End, line, col, contextLabel(f), f.Reason)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func contextLabel(f detect.Finding) string {
+	switch {
+	case !f.Keywords:
+		return "n/a"
+	case f.ContextHit:
+		return "hit"
+	default:
+		return "miss"
+	}
+}
+
+func lineCol(data []byte, offset int) (int, int) {
+	prefix := data[:offset]
+	line := bytes.Count(prefix, []byte("\n")) + 1
+	col := offset - bytes.LastIndexByte(prefix, '\n')
+	return line, col
+}
+
+// maskedPreview renders the line around [start,end) with every candidate span
+// masked and control bytes replaced, so no matched byte is ever printed.
+func maskedPreview(data []byte, start, end int, spans [][2]int) string {
+	lineStart := bytes.LastIndexByte(data[:start], '\n') + 1
+	lineEnd := len(data)
+	if idx := bytes.IndexByte(data[end:], '\n'); idx >= 0 {
+		lineEnd = end + idx
+	}
+	from := max(lineStart, start-previewContext)
+	to := min(lineEnd, end+previewContext)
+
+	var b strings.Builder
+	if from > lineStart {
+		b.WriteString("...")
+	}
+	for i := from; i < to; i++ {
+		switch {
+		case inSpans(i, spans):
+			b.WriteByte('*')
+		case data[i] < 0x20 || data[i] == 0x7f:
+			b.WriteByte('.')
+		default:
+			b.WriteByte(data[i])
+		}
+	}
+	if to < lineEnd {
+		b.WriteString("...")
+	}
+	return b.String()
+}
+
+func inSpans(i int, spans [][2]int) bool {
+	for _, s := range spans {
+		if i >= s[0] && i < s[1] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/config"
+	"github.com/suryansh-23/secretty/internal/detect"
+)
+
+func TestRulesReportNeverPrintsMatchedBytes(t *testing.T) {
+	cfg := config.DefaultConfig()
+	engine, err := detect.NewEngine(cfg)
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	secret := strings.Repeat("d", 64)
+	data := []byte("line one\nPRIVATE_KEY=0x" + secret + "\n")
+	var out bytes.Buffer
+	if err := writeRulesReport(&out, data, engine.Explain(data)); err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	report := out.String()
+	if strings.Contains(report, secret[:8]) {
+		t.Fatalf("report leaked matched bytes: %q", report)
+	}
+	for _, want := range []string{"match 1: evm_private_key", "(line 2, col 13)", "context=hit", "PRIVATE_KEY=****", "env_private_key", "typed detector preferred over regex"} {
+		if !strings.Contains(report, want) {
+			t.Fatalf("report missing %q:\n%s", want, report)
+		}
+	}
+}
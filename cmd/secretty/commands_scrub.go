@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/suryansh-23/secretty/internal/detect"
+	"github.com/suryansh-23/secretty/internal/scrub"
+)
+
+func newScrubCmd(state *appState) *cobra.Command {
+	var (
+		format  string
+		outPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "scrub [file|-]",
+		Short: "Redact a log, terminal capture, or asciicast recording offline",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := parseScrubFormat(format)
+			if err != nil {
+				return err
+			}
+			var in io.Reader = cmd.InOrStdin()
+			if len(args) == 1 && args[0] != "-" {
+				file, err := os.Open(args[0])
+				if err != nil {
+					return fmt.Errorf("open input: %w", err)
+				}
+				defer func() {
+					_ = file.Close()
+				}()
+				in = file
+			}
+			out := cmd.OutOrStdout()
+			var outFile *os.File
+			if outPath != "" && outPath != "-" {
+				file, err := os.OpenFile(outPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+				if err != nil {
+					return fmt.Errorf("open output: %w", err)
+				}
+				defer func() {
+					_ = file.Close()
+				}()
+				out, outFile = file, file
+			}
+			engine, err := detect.NewEngine(state.cfg)
+			if err != nil {
+				return err
+			}
+			if err := scrub.Run(out, in, f, scrub.Options{
+				Config:   state.cfg,
+				Detector: engine,
+				Logger:   state.logger,
+			}); err != nil {
+				return err
+			}
+			// A failed close can lose buffered output, so it fails the scrub.
+			if outFile != nil {
+				if err := outFile.Close(); err != nil {
+					return fmt.Errorf("close output: %w", err)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "auto", "input format: auto|text|asciicast")
+	cmd.Flags().StringVarP(&outPath, "output", "o", "", "write to file instead of stdout")
+	return cmd
+}
+
+func parseScrubFormat(value string) (scrub.Format, error) {
+	switch scrub.Format(value) {
+	case scrub.FormatAuto, scrub.FormatText, scrub.FormatAsciicast:
+		return scrub.Format(value), nil
+	default:
+		return "", fmt.Errorf("unsupported format %q: use auto, text, or asciicast", value)
+	}
+}
@@ -10,7 +10,8 @@ import (
 )
 
 func newShellCmd(state *appState) *cobra.Command {
-	return &cobra.Command{
+	var recordPath string
+	cmd := &cobra.Command{
 		Use:   "shell -- <shell>",
 		Short: "Start a protected interactive shell",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -28,13 +29,16 @@ func newShellCmd(state *appState) *cobra.Command {
 					return err
 				}
 			}
-			return runWithPTY(cmd.Context(), state.cfg, state.cfgPath, command, state.cache, state.logger, true)
+			return runWithPTY(cmd.Context(), state, command, true, recordPath)
 		},
 	}
+	cmd.Flags().StringVar(&recordPath, "record", "", "write an asciicast v2 recording of the redacted output")
+	return cmd
 }
 
 func newRunCmd(state *appState) *cobra.Command {
-	return &cobra.Command{
+	var recordPath string
+	cmd := &cobra.Command{
 		Use:   "run -- <cmd...>",
 		Short: "Run a command under a protected PTY",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -46,9 +50,11 @@ func newRunCmd(state *appState) *cobra.Command {
 				return errors.New("run requires a command after --")
 			}
 			command := exec.Command(runArgs[0], runArgs[1:]...)
-			return runWithPTY(cmd.Context(), state.cfg, state.cfgPath, command, state.cache, state.logger, false)
+			return runWithPTY(cmd.Context(), state, command, false, recordPath)
 		},
 	}
+	cmd.Flags().StringVar(&recordPath, "record", "", "write an asciicast v2 recording of the redacted output")
+	return cmd
 }
 
 func defaultShellCommand() *exec.Cmd {
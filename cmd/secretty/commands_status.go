@@ -64,15 +64,28 @@ func runDoctor(state *appState) error {
 	fmt.Printf("config_path=%s\n", state.cfgPath)
 	fmt.Printf("config_found=%t\n", state.cfgFound)
 	fmt.Printf("mode=%s\n", state.cfg.Mode)
+	if wd, err := os.Getwd(); err == nil {
+		overlay := config.FindOverlay(wd)
+		fmt.Printf("project_config=%s\n", overlayLabel(overlay))
+		if overlay != "" {
+			fmt.Printf("project_config_trusted=%t\n", state.cfg.TrustsOverlay(overlay))
+		}
+	}
 	fmt.Printf("strict_no_reveal=%t\n", state.cfg.Strict.NoReveal)
+	fmt.Printf("strict_lock_until_exit=%t\n", state.cfg.Strict.LockUntilExit)
 	fmt.Printf("strict_disable_copy_original=%t\n", state.cfg.Strict.DisableCopyOriginal)
+	fmt.Printf("allowlist_active=%t\n", state.cfg.Allowlist.Enabled && len(state.cfg.Allowlist.Commands) > 0 && !state.cfg.LockedUntilExit())
 	fmt.Printf("copy_enabled=%t\n", state.cfg.Overrides.CopyWithoutRender.Enabled)
 	fmt.Printf("copy_ttl_seconds=%d\n", state.cfg.Overrides.CopyWithoutRender.TTLSeconds)
 	fmt.Printf("copy_require_confirm=%t\n", state.cfg.Overrides.CopyWithoutRender.RequireConfirm)
 	fmt.Printf("status_line_enabled=%t\n", state.cfg.Redaction.StatusLine.Enabled)
 	fmt.Printf("status_line_rate_limit_ms=%d\n", state.cfg.Redaction.StatusLine.RateLimitMS)
+	fmt.Printf("rulesets_enabled=%s\n", strings.Join(enabledRulesetNames(state.cfg), ","))
 	fmt.Printf("rules_enabled=%s\n", strings.Join(enabledRuleNames(state.cfg), ","))
 	fmt.Printf("typed_detectors_enabled=%s\n", strings.Join(enabledDetectorNames(state.cfg), ","))
+	for _, line := range ruleSources(state.cfg) {
+		fmt.Printf("rule_source=%s\n", line)
+	}
 	cacheScope := "in-process"
 	if os.Getenv("SECRETTY_SOCKET") != "" {
 		cacheScope = "ipc"
@@ -81,6 +94,19 @@ func runDoctor(state *appState) error {
 	return nil
 }
 
+func enabledRulesetNames(cfg config.Config) []string {
+	var out []string
+	for _, name := range cfg.Rulesets.Names() {
+		if cfg.Rulesets[name].Enabled {
+			out = append(out, name)
+		}
+	}
+	if len(out) == 0 {
+		return []string{"none"}
+	}
+	return out
+}
+
 func enabledRuleNames(cfg config.Config) []string {
 	var out []string
 	for _, rule := range cfg.Rules {
@@ -106,3 +132,20 @@ func enabledDetectorNames(cfg config.Config) []string {
 	}
 	return out
 }
+
+// ruleSources lists "name path" for every rule and typed detector loaded from
+// a file, so included rule packs can be traced. Built-in defaults are omitted.
+func ruleSources(cfg config.Config) []string {
+	var out []string
+	for _, rule := range cfg.Rules {
+		if rule.Source != "" {
+			out = append(out, rule.Name+" "+rule.Source)
+		}
+	}
+	for _, det := range cfg.TypedDetectors {
+		if det.Source != "" {
+			out = append(out, det.Name+" "+det.Source)
+		}
+	}
+	return out
+}
@@ -31,7 +31,13 @@ func newRootCmd(state *appState) *cobra.Command {
 			}
 			cfg, found, err := config.Load(resolvedPath)
 			if err != nil {
-				return err
+				if !wrapsSession(cmd) {
+					return err
+				}
+				// A broken config must not leave an auto-wrapped login shell
+				// unusable; run with defaults until the file is fixed.
+				fmt.Fprintf(os.Stderr, "secretty: %v\nsecretty: using default config until %s is fixed\n", err, resolvedPath)
+				cfg, found = config.DefaultConfig(), true
 			}
 			applyOverrides(&cfg, strictFlag, debugFlag)
 			state.cfg = cfg
@@ -39,6 +45,17 @@ func newRootCmd(state *appState) *cobra.Command {
 			state.cache = ensureCache(state.cache, cfg)
 			state.logger = debug.New(cfg.Debug.Enabled)
 			state.cfgPath = resolvedPath
+			state.loadConfig = func() (config.Config, error) {
+				cfg, found, err := config.Load(resolvedPath)
+				if err != nil {
+					return config.Config{}, err
+				}
+				if !found {
+					return config.Config{}, fmt.Errorf("config not found: %s", resolvedPath)
+				}
+				applyOverrides(&cfg, strictFlag, debugFlag)
+				return cfg, nil
+			}
 			if !found && !noInitHints && cmd.Name() != "init" {
 				fmt.Fprintln(os.Stderr, "secretty: no config found; run `secretty init`")
 			}
@@ -46,7 +63,7 @@ func newRootCmd(state *appState) *cobra.Command {
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			command := defaultShellCommand()
-			return runWithPTY(cmd.Context(), state.cfg, state.cfgPath, command, state.cache, state.logger, true)
+			return runWithPTY(cmd.Context(), state, command, true, "")
 		},
 	}
 
@@ -61,6 +78,10 @@ func newRootCmd(state *appState) *cobra.Command {
 	rootCmd.AddCommand(newResetCmd(&cfgPath))
 	rootCmd.AddCommand(newCopyCmd(state))
 	rootCmd.AddCommand(newPauseCmd(state))
+	rootCmd.AddCommand(newExportCmd())
+	rootCmd.AddCommand(newReloadCmd())
+	rootCmd.AddCommand(newScrubCmd(state))
+	rootCmd.AddCommand(newRulesCmd(state))
 	rootCmd.AddCommand(newStatusCmd(state))
 	rootCmd.AddCommand(newDoctorCmd(state))
 	rootCmd.AddCommand(newVersionCmd())
@@ -79,6 +100,19 @@ func newRootCmd(state *appState) *cobra.Command {
 	return rootCmd
 }
 
+// wrapsSession reports whether cmd starts a wrapped PTY session.
+func wrapsSession(cmd *cobra.Command) bool {
+	if !cmd.HasParent() {
+		return true
+	}
+	switch cmd.Name() {
+	case "shell", "run":
+		return true
+	default:
+		return false
+	}
+}
+
 func applyOverrides(cfg *config.Config, strictFlag, debugFlag bool) {
 	if strictFlag {
 		cfg.Mode = types.ModeStrict
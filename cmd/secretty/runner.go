@@ -2,16 +2,20 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"regexp"
 	"strings"
 	"time"
 
 	"golang.org/x/term"
 
 	"github.com/suryansh-23/secretty/internal/allowlist"
+	"github.com/suryansh-23/secretty/internal/asciicast"
 	"github.com/suryansh-23/secretty/internal/cache"
 	"github.com/suryansh-23/secretty/internal/clipboard"
 	"github.com/suryansh-23/secretty/internal/config"
@@ -21,31 +25,49 @@ import (
 	"github.com/suryansh-23/secretty/internal/ptywrap"
 	"github.com/suryansh-23/secretty/internal/redact"
 	"github.com/suryansh-23/secretty/internal/sessioncontrol"
+	"github.com/suryansh-23/secretty/internal/transcript"
 	"github.com/suryansh-23/secretty/internal/types"
 	"github.com/suryansh-23/secretty/internal/ui"
 )
 
-func startIPCServer(cfg config.Config, cache *cache.Cache, pause *sessioncontrol.Controller) (string, func(), error) {
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func startIPCServer(cfg config.Config, cache *cache.Cache, pause *sessioncontrol.Controller, ring *transcript.Ring, reloader *sessionReloader) (string, func(), error) {
 	copyEnabled := cache != nil &&
 		cfg.Overrides.CopyWithoutRender.Enabled &&
 		(cfg.Mode != types.ModeStrict || !cfg.Strict.DisableCopyOriginal)
 	if !copyEnabled {
 		cache = nil
 	}
-	if cache == nil && pause == nil {
+	locked := cfg.LockedUntilExit()
+	if cache == nil && pause == nil && ring == nil && reloader == nil && !locked {
 		return "", nil, nil
 	}
 	socketPath, err := ipc.TempSocketPath()
 	if err != nil {
 		return "", nil, err
 	}
-	server, err := ipc.StartServer(socketPath, cache, func(payload []byte) error {
-		return clipboard.CopyBytes(cfg.Overrides.CopyWithoutRender.Backend, payload)
-	}, pause)
+	var reload func() error
+	if reloader != nil {
+		reload = reloader.Reload
+	}
+	server, err := ipc.StartServer(socketPath, ipc.ServerOptions{
+		Cache: cache,
+		CopyFn: func(payload []byte) error {
+			return clipboard.CopyBytes(cfg.Overrides.CopyWithoutRender.Backend, payload)
+		},
+		Pause:      pause,
+		Transcript: ring,
+		Locked:     locked,
+		Reload:     reload,
+	})
 	if err != nil {
 		_ = os.Remove(socketPath)
 		return "", nil, err
 	}
+	if reloader != nil {
+		reloader.lock = server.Lock
+	}
 	cleanup := func() {
 		_ = server.Close()
 		_ = os.Remove(socketPath)
@@ -53,7 +75,8 @@ func startIPCServer(cfg config.Config, cache *cache.Cache, pause *sessioncontrol
 	return socketPath, cleanup, nil
 }
 
-func runWithPTY(ctx context.Context, cfg config.Config, cfgPath string, command *exec.Cmd, cache *cache.Cache, logger *debug.Logger, interactive bool) error {
+func runWithPTY(ctx context.Context, state *appState, command *exec.Cmd, interactive bool, recordPath string) error {
+	cfg, cfgPath, logger := state.cfg, state.cfgPath, state.logger
 	command.Env = os.Environ()
 	if os.Getenv("SECRETTY_HOOK_DEBUG") != "" {
 		stdinTTY := term.IsTerminal(int(os.Stdin.Fd()))
@@ -64,18 +87,50 @@ func runWithPTY(ctx context.Context, cfg config.Config, cfgPath string, command
 	if os.Getenv("SECRETTY_WRAPPED") == "" {
 		command.Env = append(command.Env, "SECRETTY_WRAPPED=1")
 	}
+	markToken, err := newMarkToken()
+	if err != nil {
+		return err
+	}
+	command.Env = append(command.Env, "SECRETTY_MARK_TOKEN="+markToken)
 	if cfgPath != "" && os.Getenv("SECRETTY_CONFIG") == "" {
 		command.Env = append(command.Env, "SECRETTY_CONFIG="+cfgPath)
 	}
-	cleanup := func() {}
-	cacheForRun := cache
+	if interactive {
+		cfg.Redaction.RollingWindowBytes = 0
+	}
 	var pauseCtrl *sessioncontrol.Controller
-	if bypass {
-		cacheForRun = nil
-	} else if interactive {
+	if interactive {
 		pauseCtrl = sessioncontrol.NewController()
 	}
-	socketPath, closeFn, err := startIPCServer(cfg, cacheForRun, pauseCtrl)
+	var ring *transcript.Ring
+	if cfg.Transcript.Enabled {
+		ring = transcript.NewRing(cfg.Transcript.MaxBytes)
+	}
+	detector := sessionDetector(cfg)
+	stream := redact.NewStream(os.Stdout, cfg, detector, state.cache, logger, pauseCtrl)
+	stream.SetBypass(bypass)
+	stream.SetPauseBlocked(cfg.LockedUntilExit())
+	stream.SetMarkToken(markToken)
+	policies := newPolicyDetectors(detector, logger)
+	stream.SetCommandSettings(shellCommandSettings(cfg, detector, policies, logger))
+	reloader := &sessionReloader{
+		cfg:      cfg,
+		base:     cfg,
+		load:     state.loadConfig,
+		command:  command,
+		policy:   -1,
+		detector: detector,
+		policies: policies,
+		stream:   stream,
+		cache:    state.cache,
+		logger:   logger,
+	}
+	if state.loadConfig == nil {
+		reloader = nil
+	}
+
+	cleanup := func() {}
+	socketPath, closeFn, err := startIPCServer(cfg, state.cache, pauseCtrl, ring, reloader)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "secretty: session controls unavailable:", err)
 	} else if socketPath != "" {
@@ -85,27 +140,71 @@ func runWithPTY(ctx context.Context, cfg config.Config, cfgPath string, command
 		}
 	}
 	defer cleanup()
-	if interactive && !bypass {
-		cfg.Redaction.RollingWindowBytes = 0
+	if reloader != nil {
+		if wd, err := os.Getwd(); err == nil {
+			reloader.SetDir(wd)
+		}
+		stream.SetDirObserver(func(host, dir string) {
+			if isLocalHost(host) {
+				reloader.SetDir(dir)
+			}
+		})
+	}
+	if reloader != nil && cfgPath != "" {
+		watchCtx, stopWatch := context.WithCancel(ctx)
+		defer stopWatch()
+		go config.Watch(watchCtx, cfgPath, config.DefaultWatchInterval, reloader.reloadFromWatch)
 	}
 	if interactive && os.Getenv("SECRETTY_WRAPPED") == "" && cfg.UI.ShellBanner {
 		showWrapBanner(currentBadge())
 	}
-	var output io.Writer = os.Stdout
-	if !bypass {
-		detector := detect.NewEngine(cfg)
-		output = redact.NewStream(os.Stdout, cfg, detector, cacheForRun, logger, pauseCtrl)
+	var recorder *asciicast.Recorder
+	if recordPath != "" {
+		if bypass {
+			fmt.Fprintln(os.Stderr, "secretty: recording disabled: allowlisted command output is not redacted")
+		} else {
+			rec, err := asciicast.Create(recordPath)
+			if err != nil {
+				return err
+			}
+			recorder = rec
+		}
+	}
+	var tees []io.Writer
+	if ring != nil {
+		tees = append(tees, ring)
+	}
+	if recorder != nil {
+		tees = append(tees, recorder)
+	}
+	if len(tees) > 0 {
+		stream.SetTranscript(io.MultiWriter(tees...))
 	}
 	var inputObserver func([]byte)
 	if interactive && pauseCtrl != nil {
 		inputObserver = commandLineObserver(pauseCtrl)
 	}
+	var resizeObserver func(cols, rows int)
+	if recorder != nil {
+		resizeObserver = recorder.Resize
+	}
+	var foregroundObserver func([]string)
+	if reloader != nil {
+		foregroundObserver = reloader.SetForeground
+	}
 	exitCode, err := ptywrap.RunCommand(ctx, command, ptywrap.Options{
-		RawMode:       true,
-		Output:        output,
-		Logger:        logger,
-		InputObserver: inputObserver,
+		RawMode:            true,
+		Output:             stream,
+		Logger:             logger,
+		InputObserver:      inputObserver,
+		ResizeObserver:     resizeObserver,
+		ForegroundObserver: foregroundObserver,
 	})
+	if recorder != nil {
+		if closeErr := recorder.Close(); closeErr != nil {
+			fmt.Fprintln(os.Stderr, "secretty: recording incomplete:", closeErr)
+		}
+	}
 	if err != nil {
 		return err
 	}
@@ -115,6 +214,32 @@ func runWithPTY(ctx context.Context, cfg config.Config, cfgPath string, command
 	return nil
 }
 
+// sessionDetector builds the session's detector, falling back to the default
+// rules with a warning rather than failing the wrapper.
+func sessionDetector(cfg config.Config) redact.Detector {
+	engine, err := detect.NewEngine(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "secretty: %v; using default detection rules\n", err)
+		return detect.NewDefaultEngine()
+	}
+	return engine
+}
+
+// isLocalHost reports whether an OSC 7 host names this machine, so a
+// directory reported from an ssh session is not looked up locally.
+func isLocalHost(host string) bool {
+	if host == "" || strings.EqualFold(host, "localhost") {
+		return true
+	}
+	name, err := os.Hostname()
+	if err != nil {
+		return false
+	}
+	short, _, _ := strings.Cut(name, ".")
+	hostShort, _, _ := strings.Cut(host, ".")
+	return strings.EqualFold(host, name) || strings.EqualFold(hostShort, short)
+}
+
 func commandLineObserver(ctrl *sessioncontrol.Controller) func([]byte) {
 	if ctrl == nil {
 		return nil
@@ -189,10 +314,105 @@ func shouldBypassRedaction(cfg config.Config, command *exec.Cmd, logger *debug.L
 		}
 		return false
 	}
-	if matched && logger != nil {
+	if !matched {
+		return false
+	}
+	if cfg.LockedUntilExit() {
+		fmt.Fprintf(os.Stderr, "secretty: allowlist ignored for %s: strict.lock_until_exit forbids unredacted output\n", argv0)
+		return false
+	}
+	if logger != nil {
 		logger.Infof("allowlist: bypassing redaction for %s (resolved=%s)", argv0, resolved)
 	}
-	return matched
+	return true
+}
+
+// newMarkToken returns the per-session token the shell hooks add to their
+// OSC 133 marks. The hooks move it from the environment into a shell
+// variable, so commands run in the shell do not inherit it.
+func newMarkToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("session token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// shellCommandBypass returns the stream's per-command bypass for commands a
+// shell inside the session reports with OSC 133 marks: output of a command
+// whose name is allowlisted is not redacted. It returns nil when nothing can
+// be bypassed, including in sessions locked by strict.lock_until_exit.
+func shellCommandBypass(cfg config.Config, logger *debug.Logger) func(string) bool {
+	if !cfg.Allowlist.Enabled || len(cfg.Allowlist.Commands) == 0 || cfg.LockedUntilExit() {
+		return nil
+	}
+	return func(cmdline string) bool {
+		name := commandName(cmdline)
+		if name == "" {
+			return false
+		}
+		resolved := resolveCommandPath(name)
+		matched, err := allowlist.Match(cfg.Allowlist.Commands, name, resolved)
+		if err != nil {
+			if logger != nil {
+				logger.Infof("allowlist: invalid pattern: %v", err)
+			}
+			return false
+		}
+		if matched && logger != nil {
+			logger.Infof("allowlist: bypassing redaction for shell command %s (resolved=%s)", name, resolved)
+		}
+		return matched
+	}
+}
+
+// shellCommandSettings returns the stream's per-command settings for
+// commands a shell inside the session reports with OSC 133 marks. The first
+// policy matching the command line's words applies; otherwise an allowlisted
+// command's output is not redacted. It returns nil when no command can
+// change the session's settings.
+func shellCommandSettings(cfg config.Config, detector redact.Detector, policies *policyDetectors, logger *debug.Logger) func(string) *redact.CommandSettings {
+	bypass := shellCommandBypass(cfg, logger)
+	if bypass == nil && len(cfg.Policies) == 0 {
+		return nil
+	}
+	return func(cmdline string) *redact.CommandSettings {
+		allowed := bypass != nil && bypass(cmdline)
+		if i := cfg.PolicyFor(commandArgv(cmdline)); i >= 0 {
+			if logger != nil {
+				logger.Infof("policy: %s for shell command", policyLabel(cfg, i))
+			}
+			settings := policies.settings(cfg, i)
+			settings.Bypass = settings.Bypass || allowed
+			return settings
+		}
+		if allowed {
+			return &redact.CommandSettings{Config: cfg, Detector: detector, Bypass: true}
+		}
+		return nil
+	}
+}
+
+// commandName returns the program a shell command line runs, skipping
+// leading VAR=value assignments.
+func commandName(cmdline string) string {
+	if argv := commandArgv(cmdline); len(argv) > 0 {
+		return argv[0]
+	}
+	return ""
+}
+
+// commandArgv splits a shell command line into words, dropping leading
+// VAR=value assignments. Quoting is not interpreted.
+func commandArgv(cmdline string) []string {
+	fields := strings.Fields(cmdline)
+	for i, field := range fields {
+		if name, _, ok := strings.Cut(field, "="); ok && envNamePattern.MatchString(name) {
+			continue
+		}
+		return fields[i:]
+	}
+	return nil
 }
 
 func resolveCommandPath(argv0 string) string {
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/config"
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+func TestShouldBypassRedactionHonorsLock(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Allowlist.Enabled = true
+	cfg.Allowlist.Commands = []string{"true"}
+	command := exec.Command("true")
+
+	if !shouldBypassRedaction(cfg, command, nil) {
+		t.Fatal("expected allowlisted command to bypass redaction on the default config")
+	}
+
+	cfg.Mode = types.ModeDemo
+	if !shouldBypassRedaction(cfg, command, nil) {
+		t.Fatal("expected allowlisted command to bypass redaction in demo mode")
+	}
+
+	cfg.Mode = types.ModeStrict
+	cfg.Strict.LockUntilExit = true
+	if shouldBypassRedaction(cfg, command, nil) {
+		t.Fatal("expected strict lock_until_exit to keep redaction on")
+	}
+}
+
+func TestShellCommandBypassMatchesCommandName(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Allowlist.Enabled = true
+	cfg.Allowlist.Commands = []string{"vim", "kubectl*"}
+	cfg.Mode = types.ModeDemo
+
+	bypass := shellCommandBypass(cfg, nil)
+	if bypass == nil {
+		t.Fatal("expected a per-command bypass")
+	}
+	for cmdline, want := range map[string]bool{
+		"vim secrets.env":              true,
+		"EDITOR=nano LANG=C vim notes": true,
+		"kubectl-foo get pods":         true,
+		"cat secrets.env | vim -":      false,
+		"FOO=bar":                      false,
+		"":                             false,
+	} {
+		if got := bypass(cmdline); got != want {
+			t.Fatalf("bypass(%q) = %t, want %t", cmdline, got, want)
+		}
+	}
+
+	cfg.Mode = types.ModeStrict
+	if shellCommandBypass(cfg, nil) == nil {
+		t.Fatal("expected strict no_reveal to keep the per-command bypass")
+	}
+	cfg.Strict.LockUntilExit = true
+	if shellCommandBypass(cfg, nil) != nil {
+		t.Fatal("expected strict lock_until_exit to disable the per-command bypass")
+	}
+}
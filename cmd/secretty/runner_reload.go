@@ -0,0 +1,255 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/suryansh-23/secretty/internal/cache"
+	"github.com/suryansh-23/secretty/internal/config"
+	"github.com/suryansh-23/secretty/internal/debug"
+	"github.com/suryansh-23/secretty/internal/detect"
+	"github.com/suryansh-23/secretty/internal/redact"
+)
+
+var errReloadLocked = errors.New("config is locked for this session by strict.lock_until_exit")
+
+// sessionReloader applies config edits and per-project overlays to a running
+// session.
+type sessionReloader struct {
+	mu  sync.Mutex
+	cfg config.Config
+	// base is the user config before any project overlay.
+	base config.Config
+	// dir is the shell's last reported working directory and overlay the
+	// project file found for it, if any.
+	dir     string
+	overlay string
+	// argv is the terminal's foreground command and policy the index of
+	// the policy it matched, or -1. policies holds the current config's
+	// policy detectors.
+	argv     []string
+	policy   int
+	detector redact.Detector
+	policies *policyDetectors
+	load     func() (config.Config, error)
+	command  *exec.Cmd
+	stream   *redact.Stream
+	cache    *cache.Cache
+	logger   *debug.Logger
+	// lock is called when a reloaded config enables strict.lock_until_exit.
+	lock func()
+}
+
+// Reload re-reads the config and swaps it into the session. A config that
+// fails validation is rejected and the current one is kept.
+func (r *sessionReloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cfg.LockedUntilExit() {
+		return errReloadLocked
+	}
+	base, err := r.load()
+	if err != nil {
+		return fmt.Errorf("config rejected: %w", err)
+	}
+	overlay := config.FindOverlay(r.dir)
+	cfg, _ := r.withOverlay(base, overlay)
+	detector, err := detect.NewEngine(cfg)
+	if err != nil {
+		return fmt.Errorf("config rejected: %w", err)
+	}
+	r.base, r.overlay = base, overlay
+	r.apply(cfg, detector)
+	return nil
+}
+
+// SetDir records the shell's working directory and switches to the project
+// overlay found for it, reporting the switch on stderr. Nothing changes while
+// the nearest overlay stays the same, or once strict.lock_until_exit has
+// locked the session.
+func (r *sessionReloader) SetDir(dir string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dir = dir
+	overlay := config.FindOverlay(dir)
+	if overlay == r.overlay || r.cfg.LockedUntilExit() {
+		return
+	}
+	cfg, applied := r.withOverlay(r.base, overlay)
+	detector, err := detect.NewEngine(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\r\nsecretty: project config %s skipped: %v\r\n", overlay, err)
+		cfg, applied = r.base, false
+		if detector, err = detect.NewEngine(cfg); err != nil {
+			return
+		}
+	}
+	r.overlay = overlay
+	r.apply(cfg, detector)
+	if applied {
+		fmt.Fprintf(os.Stderr, "\r\nsecretty: %s\r\n", overlayNotice(r.base, overlay))
+	}
+	if r.logger != nil {
+		r.logger.Infof("project config: %s", overlayLabel(overlay))
+	}
+}
+
+// overlayNotice describes the project overlay now applied over base.
+func overlayNotice(base config.Config, overlay string) string {
+	switch {
+	case overlay == "":
+		return "project config cleared"
+	case base.TrustsOverlay(overlay):
+		return "project config " + overlay + " applied"
+	default:
+		return "project config " + overlay + " applied (untrusted: it can only tighten your config)"
+	}
+}
+
+// withOverlay layers the overlay at path over base and reports whether it
+// applied. A broken overlay is reported and skipped so the user config still
+// applies.
+func (r *sessionReloader) withOverlay(base config.Config, path string) (config.Config, bool) {
+	if path == "" {
+		return base, true
+	}
+	cfg, err := config.ApplyOverlay(base, path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\r\nsecretty: project config skipped: %v\r\n", err)
+		return base, false
+	}
+	return cfg, true
+}
+
+func overlayLabel(path string) string {
+	if path == "" {
+		return "none"
+	}
+	return path
+}
+
+func (r *sessionReloader) reloadFromWatch() {
+	if err := r.Reload(); err != nil {
+		fmt.Fprintf(os.Stderr, "\r\nsecretty: config reload skipped: %v\r\n", err)
+		return
+	}
+	if r.logger != nil {
+		r.logger.Infof("config reloaded")
+	}
+}
+
+func (r *sessionReloader) apply(cfg config.Config, detector redact.Detector) {
+	// The stream keeps the window size it started with.
+	cfg.Redaction.RollingWindowBytes = r.cfg.Redaction.RollingWindowBytes
+	r.cfg, r.detector = cfg, detector
+	r.policies = newPolicyDetectors(detector, r.logger)
+	r.policy = cfg.PolicyFor(r.argv)
+	r.configureStream()
+	if r.cache != nil && ensureCache(r.cache, cfg) == nil {
+		r.cache.Clear()
+	}
+	if cfg.LockedUntilExit() && r.lock != nil {
+		r.lock()
+	}
+}
+
+// SetForeground records the terminal's foreground command and applies the
+// first policy that matches it.
+func (r *sessionReloader) SetForeground(argv []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.argv = argv
+	policy := r.cfg.PolicyFor(argv)
+	if policy == r.policy {
+		return
+	}
+	r.policy = policy
+	r.configureStream()
+	if r.logger != nil {
+		r.logger.Infof("policy: %s", policyLabel(r.cfg, policy))
+	}
+}
+
+// configureStream applies the session config to the stream, with the
+// foreground command's policy layered over it. A command the shell reports
+// gets its policy from its command line instead, from its first byte of
+// output.
+func (r *sessionReloader) configureStream() {
+	cfg := r.cfg
+	if r.detector == nil {
+		r.detector = sessionDetector(cfg)
+	}
+	if r.policies == nil {
+		r.policies = newPolicyDetectors(r.detector, r.logger)
+	}
+	detector := r.detector
+	bypass := shouldBypassRedaction(cfg, r.command, r.logger)
+	pauseBlocked := cfg.LockedUntilExit()
+	if r.policy >= 0 {
+		settings := r.policies.settings(cfg, r.policy)
+		cfg, detector = settings.Config, settings.Detector
+		bypass = bypass || settings.Bypass
+		pauseBlocked = settings.PauseBlocked
+	}
+	r.stream.SetCommandSettings(shellCommandSettings(r.cfg, r.detector, r.policies, r.logger))
+	r.stream.Reconfigure(cfg, detector, bypass)
+	r.stream.SetPauseBlocked(pauseBlocked)
+}
+
+// policyDetectors builds and caches a detector per policy of one config. The
+// stream asks for them under its own lock, so the cache has its own rather
+// than sharing the reloader's.
+type policyDetectors struct {
+	mu       sync.Mutex
+	fallback redact.Detector
+	logger   *debug.Logger
+	engines  map[int]redact.Detector
+}
+
+func newPolicyDetectors(fallback redact.Detector, logger *debug.Logger) *policyDetectors {
+	return &policyDetectors{fallback: fallback, logger: logger, engines: map[int]redact.Detector{}}
+}
+
+// settings returns the stream settings for the policy at index i of cfg.
+func (p *policyDetectors) settings(cfg config.Config, i int) *redact.CommandSettings {
+	policy := cfg.Policies[i]
+	cfg = cfg.WithPolicy(policy)
+	return &redact.CommandSettings{
+		Config:       cfg,
+		Detector:     p.detector(cfg, i),
+		Bypass:       policy.Mode == config.PolicyModeOff && !cfg.LockedUntilExit(),
+		PauseBlocked: !policy.Pausable() || cfg.LockedUntilExit(),
+	}
+}
+
+// detector returns the detector for policy i, whose config is cfg, falling
+// back to the session detector when the policy's rulesets do not build.
+func (p *policyDetectors) detector(cfg config.Config, i int) redact.Detector {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if detector, ok := p.engines[i]; ok {
+		return detector
+	}
+	detector := p.fallback
+	if engine, err := detect.NewEngine(cfg); err == nil {
+		detector = engine
+	} else if p.logger != nil {
+		p.logger.Infof("policy: detectors unavailable: %v", err)
+	}
+	p.engines[i] = detector
+	return detector
+}
+
+func policyLabel(cfg config.Config, policy int) string {
+	if policy < 0 {
+		return "none"
+	}
+	p := cfg.Policies[policy]
+	if p.Args == "" {
+		return p.Command
+	}
+	return p.Command + " " + p.Args
+}
@@ -0,0 +1,140 @@
+package ansi
+
+import (
+	"bytes"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// WorkingDirectory parses an OSC 7 sequence (ESC ] 7 ; file://host/path,
+// terminated by BEL or ST) as emitted by shells to report their current
+// directory. It returns the host and the path, percent-decoded when valid.
+func WorkingDirectory(seq []byte) (host, dir string, ok bool) {
+	body, _, ok := oscBody(seq, "7;")
+	if !ok {
+		return "", "", false
+	}
+	rest, found := bytes.CutPrefix(body, []byte("file://"))
+	if !found {
+		return "", "", false
+	}
+	slash := bytes.IndexByte(rest, '/')
+	if slash < 0 {
+		return "", "", false
+	}
+	host, dir = string(rest[:slash]), string(rest[slash:])
+	// Shells differ in how much of the path they percent-encode; keep the
+	// raw path when it does not decode.
+	if decoded, err := url.PathUnescape(dir); err == nil {
+		dir = decoded
+	}
+	return host, dir, true
+}
+
+// Semantic prompt mark kinds (OSC 133, from FinalTerm).
+const (
+	MarkPromptStart  = 'A'
+	MarkCommandInput = 'B'
+	MarkCommandStart = 'C'
+	MarkCommandEnd   = 'D'
+)
+
+// SemanticMark is an OSC 133 shell integration mark.
+type SemanticMark struct {
+	Kind byte
+	// Command is the command line carried by a MarkCommandStart in a
+	// cmdline= (raw) or cmdline_url= (percent-encoded) parameter.
+	Command string
+	// ExitCode is the status reported by a MarkCommandEnd, when HasExitCode.
+	ExitCode    int
+	HasExitCode bool
+	// Token is the secretty= parameter the installed shell hooks add so a
+	// session can tell their marks from ones printed by command output.
+	Token string
+}
+
+// ParseSemanticMark parses an OSC 133 sequence (ESC ] 133 ; kind [; params],
+// terminated by BEL or ST).
+func ParseSemanticMark(seq []byte) (SemanticMark, bool) {
+	body, _, ok := oscBody(seq, "133;")
+	if !ok || len(body) == 0 || (len(body) > 1 && body[1] != ';') {
+		return SemanticMark{}, false
+	}
+	mark := SemanticMark{Kind: body[0]}
+	switch mark.Kind {
+	case MarkPromptStart, MarkCommandInput, MarkCommandStart, MarkCommandEnd:
+	default:
+		return SemanticMark{}, false
+	}
+	params := string(body[1:])
+	// The raw command line may itself contain ';', so it runs to the end of
+	// the sequence.
+	if i := indexParam(params, "cmdline="); i >= 0 {
+		if mark.Kind == MarkCommandStart {
+			mark.Command = params[i+len("cmdline="):]
+		}
+		params = params[:i-1]
+	}
+	for i, param := range strings.Split(strings.TrimPrefix(params, ";"), ";") {
+		switch {
+		case strings.HasPrefix(param, "secretty="):
+			mark.Token = strings.TrimPrefix(param, "secretty=")
+		case strings.HasPrefix(param, "cmdline_url=") && mark.Kind == MarkCommandStart:
+			mark.Command = strings.TrimPrefix(param, "cmdline_url=")
+			if decoded, err := url.PathUnescape(mark.Command); err == nil {
+				mark.Command = decoded
+			}
+		case i == 0 && mark.Kind == MarkCommandEnd:
+			if code, err := strconv.Atoi(param); err == nil {
+				mark.ExitCode, mark.HasExitCode = code, true
+			}
+		}
+	}
+	return mark, true
+}
+
+// StripMarkParams returns an OSC 133 mark with only its kind and exit status,
+// so neither the command line a user typed nor the session token is passed
+// on to the terminal or to where output is recorded. Other sequences are
+// returned unchanged.
+func StripMarkParams(seq []byte) []byte {
+	mark, ok := ParseSemanticMark(seq)
+	if !ok {
+		return seq
+	}
+	_, term, _ := oscBody(seq, "133;")
+	out := append([]byte("\x1b]133;"), mark.Kind)
+	if mark.HasExitCode {
+		out = append(out, ';')
+		out = strconv.AppendInt(out, int64(mark.ExitCode), 10)
+	}
+	return append(out, term...)
+}
+
+// oscBody returns the payload of an OSC sequence whose command starts with
+// prefix, and its BEL or ST terminator.
+func oscBody(seq []byte, prefix string) (body, term []byte, ok bool) {
+	body, found := bytes.CutPrefix(seq, []byte("\x1b]"+prefix))
+	if !found {
+		return nil, nil, false
+	}
+	switch {
+	case bytes.HasSuffix(body, []byte("\x07")):
+		return body[:len(body)-1], body[len(body)-1:], true
+	case bytes.HasSuffix(body, []byte("\x1b\\")):
+		return body[:len(body)-2], body[len(body)-2:], true
+	}
+	return nil, nil, false
+}
+
+// indexParam returns the index of the parameter key in a ";"-prefixed
+// parameter list, or -1.
+func indexParam(params, key string) int {
+	for i := 0; i < len(params); i++ {
+		if params[i] == ';' && strings.HasPrefix(params[i+1:], key) {
+			return i + 1
+		}
+	}
+	return -1
+}
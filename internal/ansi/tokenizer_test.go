@@ -63,3 +63,82 @@ func TestTokenizerSingleEscape(t *testing.T) {
 		t.Fatalf("segment 1 unexpected: %#v", segs[1])
 	}
 }
+
+func TestWorkingDirectoryFromOSC7(t *testing.T) {
+	segs := collect("$ ", "\x1b]7;file://devbox/home/me/my%20repo\x07", "next")
+	if len(segs) != 3 || segs[1].Kind != SegmentEscape {
+		t.Fatalf("unexpected segments: %#v", segs)
+	}
+	host, dir, ok := WorkingDirectory(segs[1].Bytes)
+	if !ok || host != "devbox" || dir != "/home/me/my repo" {
+		t.Fatalf("got host=%q dir=%q ok=%t", host, dir, ok)
+	}
+	if _, dir, ok := WorkingDirectory([]byte("\x1b]7;file:///srv/app\x1b\\")); !ok || dir != "/srv/app" {
+		t.Fatalf("expected ST-terminated OSC 7 to parse, got %q %t", dir, ok)
+	}
+	if _, dir, ok := WorkingDirectory([]byte("\x1b]7;file://devbox/tmp/100% done #1\x07")); !ok || dir != "/tmp/100% done #1" {
+		t.Fatalf("expected unencoded path to be kept raw, got %q %t", dir, ok)
+	}
+	if _, _, ok := WorkingDirectory([]byte("\x1b]0;title\x07")); ok {
+		t.Fatalf("expected window title to be ignored")
+	}
+}
+
+func TestSemanticMarksFromOSC133(t *testing.T) {
+	segs := collect("$ \x1b]133;C;cmdline=FOO=1 vim a;b.txt\x07out\x1b]133;D;130\x1b\\")
+	if len(segs) != 4 {
+		t.Fatalf("segments = %d", len(segs))
+	}
+	mark, ok := ParseSemanticMark(segs[1].Bytes)
+	if !ok || mark.Kind != MarkCommandStart || mark.Command != "FOO=1 vim a;b.txt" {
+		t.Fatalf("command start = %+v ok=%t", mark, ok)
+	}
+	mark, ok = ParseSemanticMark(segs[3].Bytes)
+	if !ok || mark.Kind != MarkCommandEnd || !mark.HasExitCode || mark.ExitCode != 130 {
+		t.Fatalf("command end = %+v ok=%t", mark, ok)
+	}
+	if mark, ok := ParseSemanticMark([]byte("\x1b]133;C;cmdline_url=less%20%2Fetc%2Fhosts;k=v\x07")); !ok || mark.Command != "less /etc/hosts" {
+		t.Fatalf("cmdline_url = %+v ok=%t", mark, ok)
+	}
+	if mark, ok := ParseSemanticMark([]byte("\x1b]133;D\x07")); !ok || mark.HasExitCode {
+		t.Fatalf("bare command end = %+v ok=%t", mark, ok)
+	}
+	if mark, ok := ParseSemanticMark([]byte("\x1b]133;A;aid=12\x07")); !ok || mark.Kind != MarkPromptStart {
+		t.Fatalf("prompt start = %+v ok=%t", mark, ok)
+	}
+	for _, seq := range []string{"\x1b]133;Z\x07", "\x1b]133;AB\x07", "\x1b]1337;C\x07", "\x1b]133;C"} {
+		if _, ok := ParseSemanticMark([]byte(seq)); ok {
+			t.Fatalf("expected %q to be rejected", seq)
+		}
+	}
+}
+
+func TestSemanticMarkToken(t *testing.T) {
+	mark, ok := ParseSemanticMark([]byte("\x1b]133;C;secretty=f00d;cmdline=echo a;secretty=spoof\x07"))
+	if !ok || mark.Token != "f00d" || mark.Command != "echo a;secretty=spoof" {
+		t.Fatalf("command start = %+v ok=%t", mark, ok)
+	}
+	mark, ok = ParseSemanticMark([]byte("\x1b]133;D;2;secretty=f00d\x07"))
+	if !ok || mark.Token != "f00d" || !mark.HasExitCode || mark.ExitCode != 2 {
+		t.Fatalf("command end = %+v ok=%t", mark, ok)
+	}
+	if mark, _ := ParseSemanticMark([]byte("\x1b]133;C;cmdline=vim\x07")); mark.Token != "" {
+		t.Fatalf("expected no token, got %q", mark.Token)
+	}
+}
+
+func TestStripMarkParams(t *testing.T) {
+	cases := map[string]string{
+		"\x1b]133;C;secretty=f00d;cmdline=export TOKEN=abc\x07": "\x1b]133;C\x07",
+		"\x1b]133;C;cmdline_url=ls\x1b\\":                       "\x1b]133;C\x1b\\",
+		"\x1b]133;C\x07":                                        "\x1b]133;C\x07",
+		"\x1b]133;D;0;secretty=f00d\x07":                        "\x1b]133;D;0\x07",
+		"\x1b]133;A;secretty=f00d\x07":                          "\x1b]133;A\x07",
+		"\x1b]7;file://host/tmp\x07":                            "\x1b]7;file://host/tmp\x07",
+	}
+	for in, want := range cases {
+		if got := string(StripMarkParams([]byte(in))); got != want {
+			t.Fatalf("StripMarkParams(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package asciicast
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// Header is the first line of an asciicast v2 file.
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Recorder writes output and resize events as an asciicast v2 stream.
+// Write never fails so a broken recording cannot stall the session; the
+// first error is reported by Err and Close.
+type Recorder struct {
+	mu      sync.Mutex
+	w       *bufio.Writer
+	closer  io.Closer
+	start   time.Time
+	now     func() time.Time
+	header  Header
+	started bool
+	pending []byte
+	err     error
+}
+
+// Create opens path for writing and returns a recorder for it.
+func Create(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("create recording: %w", err)
+	}
+	return NewRecorder(f), nil
+}
+
+// NewRecorder returns a recorder writing to w. If w is an io.Closer it is
+// closed by Close.
+func NewRecorder(w io.Writer) *Recorder {
+	r := &Recorder{
+		w:   bufio.NewWriter(w),
+		now: time.Now,
+		header: Header{
+			Version: 2,
+			Width:   defaultWidth,
+			Height:  defaultHeight,
+			Env: map[string]string{
+				"SHELL": os.Getenv("SHELL"),
+				"TERM":  os.Getenv("TERM"),
+			},
+		},
+	}
+	if c, ok := w.(io.Closer); ok {
+		r.closer = c
+	}
+	r.start = r.now()
+	return r
+}
+
+// Resize records a terminal size. Before the first output event it sets the
+// header size; afterwards it emits an "r" event.
+func (r *Recorder) Resize(cols, rows int) {
+	if r == nil || cols <= 0 || rows <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.started {
+		r.header.Width = cols
+		r.header.Height = rows
+		return
+	}
+	r.writeEventLocked("r", fmt.Sprintf("%dx%d", cols, rows))
+}
+
+// Write records p as an output event. Incomplete UTF-8 sequences are held
+// until the next write so every event carries valid text.
+func (r *Recorder) Write(p []byte) (int, error) {
+	if r == nil {
+		return len(p), nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data := append(r.pending, p...)
+	cut := completeUTF8Len(data)
+	r.pending = append([]byte(nil), data[cut:]...)
+	if cut > 0 {
+		r.writeEventLocked("o", string(data[:cut]))
+	}
+	return len(p), nil
+}
+
+// Err returns the first write error, if any.
+func (r *Recorder) Err() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Close flushes pending output and closes the underlying writer.
+func (r *Recorder) Close() error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pending) > 0 {
+		r.writeEventLocked("o", string(r.pending))
+		r.pending = nil
+	}
+	r.ensureHeaderLocked()
+	if err := r.w.Flush(); err != nil && r.err == nil {
+		r.err = err
+	}
+	if r.closer != nil {
+		if err := r.closer.Close(); err != nil && r.err == nil {
+			r.err = err
+		}
+		r.closer = nil
+	}
+	return r.err
+}
+
+func (r *Recorder) ensureHeaderLocked() {
+	if r.started {
+		return
+	}
+	r.started = true
+	r.header.Timestamp = r.start.Unix()
+	r.writeLineLocked(r.header)
+}
+
+func (r *Recorder) writeEventLocked(kind, data string) {
+	r.ensureHeaderLocked()
+	elapsed := r.now().Sub(r.start).Seconds()
+	r.writeLineLocked([]any{elapsed, kind, data})
+	if r.err == nil {
+		if err := r.w.Flush(); err != nil {
+			r.err = err
+		}
+	}
+}
+
+func (r *Recorder) writeLineLocked(v any) {
+	if r.err != nil {
+		return
+	}
+	line, err := json.Marshal(v)
+	if err != nil {
+		r.err = err
+		return
+	}
+	line = append(line, '\n')
+	if _, err := r.w.Write(line); err != nil {
+		r.err = err
+	}
+}
+
+func completeUTF8Len(buf []byte) int {
+	start := len(buf) - 1
+	for start >= 0 && len(buf)-start < utf8.UTFMax && !utf8.RuneStart(buf[start]) {
+		start--
+	}
+	if start < 0 || utf8.FullRune(buf[start:]) {
+		return len(buf)
+	}
+	return start
+}
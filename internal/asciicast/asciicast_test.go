@@ -0,0 +1,56 @@
+package asciicast
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorderWritesHeaderEventsAndResize(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRecorder(&buf)
+	base := time.Unix(1000, 0)
+	r.start = base
+	r.now = func() time.Time { return base.Add(1500 * time.Millisecond) }
+
+	r.Resize(120, 40)
+	if _, err := r.Write([]byte("hello \xe2\x96")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := r.Write([]byte("\x88\r\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	r.Resize(100, 30)
+	if err := r.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("lines = %d: %q", len(lines), buf.String())
+	}
+	var header Header
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatalf("header: %v", err)
+	}
+	if header.Version != 2 || header.Width != 120 || header.Height != 40 || header.Timestamp != 1000 {
+		t.Fatalf("unexpected header: %+v", header)
+	}
+	var first, second, resize []any
+	for i, dst := range []*[]any{&first, &second, &resize} {
+		if err := json.Unmarshal([]byte(lines[i+1]), dst); err != nil {
+			t.Fatalf("event %d: %v", i, err)
+		}
+	}
+	if first[0].(float64) != 1.5 || first[1] != "o" || first[2] != "hello " {
+		t.Fatalf("unexpected first event: %v", first)
+	}
+	if second[2] != "█\r\n" {
+		t.Fatalf("expected split rune joined, got %q", second[2])
+	}
+	if resize[1] != "r" || resize[2] != "100x30" {
+		t.Fatalf("unexpected resize event: %v", resize)
+	}
+}
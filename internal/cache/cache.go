@@ -154,6 +154,17 @@ func (c *Cache) SetTTL(ttl time.Duration) {
 	c.ttl = ttl
 }
 
+// Clear drops every cached secret.
+func (c *Cache) Clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Init()
+	c.byID = make(map[int]*list.Element)
+}
+
 // SetMaxEntries updates the max entries and evicts if needed.
 func (c *Cache) SetMaxEntries(maxEntries int) {
 	if c == nil {
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/suryansh-23/secretty/internal/types"
@@ -28,10 +29,27 @@ type Config struct {
 	Mode   types.Mode `yaml:"mode"`
 	Strict Strict     `yaml:"strict"`
 
-	Redaction Redaction `yaml:"redaction"`
-	Masking   Masking   `yaml:"masking"`
-	Overrides Overrides `yaml:"overrides"`
-	Allowlist Allowlist `yaml:"allowlist"`
+	Redaction  Redaction  `yaml:"redaction"`
+	Masking    Masking    `yaml:"masking"`
+	Overrides  Overrides  `yaml:"overrides"`
+	Allowlist  Allowlist  `yaml:"allowlist"`
+	Transcript Transcript `yaml:"transcript"`
+
+	// Policies adjust redaction per foreground command; the first match
+	// applies.
+	Policies []Policy `yaml:"policies,omitempty"`
+
+	KnownSecrets KnownSecrets `yaml:"known_secrets"`
+	Decode       Decode       `yaml:"decode"`
+
+	// Include lists rule pack files, directories or globs merged after
+	// this file's rules. Relative paths resolve against the config's
+	// directory.
+	Include []string `yaml:"include,omitempty"`
+
+	// TrustedOverlays lists project directories whose overlay may loosen
+	// this config. Overlays elsewhere can only tighten it.
+	TrustedOverlays []string `yaml:"trusted_overlays,omitempty"`
 
 	Rulesets       Rulesets        `yaml:"rulesets"`
 	Rules          []Rule          `yaml:"rules"`
@@ -65,7 +83,14 @@ type Redaction struct {
 	PlaceholderTemplate string       `yaml:"placeholder_template"`
 	IncludeEventID      bool         `yaml:"include_event_id"`
 	RollingWindowBytes  int          `yaml:"rolling_window_bytes"`
+	BlockMaxBytes       int          `yaml:"block_max_bytes"`
 	StatusLine          StatusLine   `yaml:"status_line"`
+	Warn                Warn         `yaml:"warn"`
+}
+
+// Warn configures warn-mode highlighting.
+type Warn struct {
+	Highlight types.WarnHighlight `yaml:"highlight"`
 }
 
 // StatusLine controls optional UI hints.
@@ -99,32 +124,37 @@ type Allowlist struct {
 	Commands []string `yaml:"commands,omitempty"`
 }
 
-// CopyWithoutRender configures clipboard behavior.
-type CopyWithoutRender struct {
-	Enabled        bool   `yaml:"enabled"`
-	TTLSeconds     int    `yaml:"ttl_seconds"`
-	RequireConfirm bool   `yaml:"require_confirm"`
-	Backend        string `yaml:"backend"`
+// Transcript configures the in-memory ring of sanitized output used by export.
+type Transcript struct {
+	Enabled  bool `yaml:"enabled"`
+	MaxBytes int  `yaml:"max_bytes"`
 }
 
-// Rulesets enables higher-level rulesets.
-type Rulesets struct {
-	Web3       Web3Ruleset    `yaml:"web3"`
-	APIKeys    GenericRuleset `yaml:"api_keys"`
-	AuthTokens GenericRuleset `yaml:"auth_tokens"`
-	Cloud      GenericRuleset `yaml:"cloud"`
-	Passwords  GenericRuleset `yaml:"passwords"`
+// KnownSecrets configures exact-value detection of the user's own secrets,
+// loaded at session start from env vars, .env files and value files.
+type KnownSecrets struct {
+	Enabled   bool         `yaml:"enabled"`
+	Env       []string     `yaml:"env,omitempty"`
+	EnvFiles  []string     `yaml:"env_files,omitempty"`
+	Files     []string     `yaml:"files,omitempty"`
+	MinLength int          `yaml:"min_length"`
+	Action    types.Action `yaml:"action"`
 }
 
-// Web3Ruleset enables Web3-specific detection.
-type Web3Ruleset struct {
-	Enabled        bool `yaml:"enabled"`
-	AllowBare64Hex bool `yaml:"allow_bare_64hex"`
+// Decode configures decode-and-scan of base64, percent-encoded and
+// JSON-escaped spans.
+type Decode struct {
+	Enabled      bool `yaml:"enabled"`
+	MaxDepth     int  `yaml:"max_depth"`
+	MaxSpanBytes int  `yaml:"max_span_bytes"`
 }
 
-// GenericRuleset toggles a ruleset group.
-type GenericRuleset struct {
-	Enabled bool `yaml:"enabled"`
+// CopyWithoutRender configures clipboard behavior.
+type CopyWithoutRender struct {
+	Enabled        bool   `yaml:"enabled"`
+	TTLSeconds     int    `yaml:"ttl_seconds"`
+	RequireConfirm bool   `yaml:"require_confirm"`
+	Backend        string `yaml:"backend"`
 }
 
 // RuleType indicates how a rule is evaluated.
@@ -146,6 +176,10 @@ type Rule struct {
 	Ruleset         string           `yaml:"ruleset,omitempty"`
 	Regex           *RegexRule       `yaml:"regex,omitempty"`
 	ContextKeywords []string         `yaml:"context_keywords,omitempty"`
+
+	// Source is the file the rule was loaded from, or empty for a
+	// built-in default.
+	Source string `yaml:"-"`
 }
 
 // RegexRule configures regex-based detection.
@@ -164,6 +198,10 @@ type TypedDetector struct {
 	SecretType      types.SecretType `yaml:"secret_type"`
 	Ruleset         string           `yaml:"ruleset,omitempty"`
 	ContextKeywords []string         `yaml:"context_keywords,omitempty"`
+
+	// Source is the file the detector was loaded from, or empty for a
+	// built-in default.
+	Source string `yaml:"-"`
 }
 
 // DefaultConfig returns the canonical default configuration.
@@ -181,10 +219,14 @@ func DefaultConfig() Config {
 			PlaceholderTemplate: defaultPlaceholderTemplate,
 			IncludeEventID:      false,
 			RollingWindowBytes:  32768,
+			BlockMaxBytes:       65536,
 			StatusLine: StatusLine{
 				Enabled:     true,
 				RateLimitMS: 2000,
 			},
+			Warn: Warn{
+				Highlight: types.WarnHighlightReverse,
+			},
 		},
 		Masking: Masking{
 			Style:     types.MaskStyleGlow,
@@ -215,24 +257,21 @@ func DefaultConfig() Config {
 			Enabled:  false,
 			Commands: nil,
 		},
-		Rulesets: Rulesets{
-			Web3: Web3Ruleset{
-				Enabled:        true,
-				AllowBare64Hex: false,
-			},
-			APIKeys: GenericRuleset{
-				Enabled: false,
-			},
-			AuthTokens: GenericRuleset{
-				Enabled: false,
-			},
-			Cloud: GenericRuleset{
-				Enabled: false,
-			},
-			Passwords: GenericRuleset{
-				Enabled: false,
-			},
+		Transcript: Transcript{
+			Enabled:  true,
+			MaxBytes: 262144,
+		},
+		KnownSecrets: KnownSecrets{
+			Enabled:   false,
+			MinLength: 8,
+			Action:    types.ActionMask,
 		},
+		Decode: Decode{
+			Enabled:      false,
+			MaxDepth:     2,
+			MaxSpanBytes: 4096,
+		},
+		Rulesets: DefaultRulesets(),
 		Rules: []Rule{
 			{
 				Name:       "env_private_key",
@@ -241,7 +280,7 @@ func DefaultConfig() Config {
 				Action:     types.ActionMask,
 				Severity:   types.SeverityHigh,
 				SecretType: types.SecretEvmPrivateKey,
-				Ruleset:    "web3",
+				Ruleset:    RulesetWeb3,
 				Regex: &RegexRule{
 					Pattern: "(?i)\\bPRIVATE_KEY\\s*=\\s*([^\\s]+)",
 					Group:   1,
@@ -255,7 +294,7 @@ func DefaultConfig() Config {
 				Action:     types.ActionMask,
 				Severity:   types.SeverityHigh,
 				SecretType: types.SecretAPIKey,
-				Ruleset:    "api_keys",
+				Ruleset:    RulesetAPIKeys,
 				Regex: &RegexRule{
 					Pattern: "(?i)\\b([A-Z0-9_]*API[_-]?KEY|x-api-key|client[_-]?secret|secret[_-]?key)\\b\\s*[:=]\\s*([A-Za-z0-9_\\-]{16,})",
 					Group:   2,
@@ -269,9 +308,9 @@ func DefaultConfig() Config {
 				Action:     types.ActionMask,
 				Severity:   types.SeverityHigh,
 				SecretType: types.SecretAPIKey,
-				Ruleset:    "api_keys",
+				Ruleset:    RulesetAPIKeys,
 				Regex: &RegexRule{
-					Pattern: "\\b(sk_(live|test)_[0-9a-zA-Z]{16,})\\b",
+					Pattern: "\\b([rs]k_(live|test)_[0-9a-zA-Z]{16,})\\b",
 					Group:   1,
 				},
 			},
@@ -282,12 +321,129 @@ func DefaultConfig() Config {
 				Action:     types.ActionMask,
 				Severity:   types.SeverityHigh,
 				SecretType: types.SecretAPIKey,
-				Ruleset:    "api_keys",
+				Ruleset:    RulesetAPIKeys,
 				Regex: &RegexRule{
 					Pattern: "\\bghp_[A-Za-z0-9]{36}\\b",
 					Group:   0,
 				},
 			},
+			{
+				Name:       "stripe_webhook_secret",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretAPIKey,
+				Ruleset:    RulesetAPIKeys,
+				Regex: &RegexRule{
+					Pattern: "\\b(whsec_[A-Za-z0-9]{32,})\\b",
+					Group:   1,
+				},
+			},
+			{
+				Name:       "gitlab_pat",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretAPIKey,
+				Ruleset:    RulesetAPIKeys,
+				Regex: &RegexRule{
+					Pattern: "\\b(glpat-[A-Za-z0-9_\\-]{20,}(?:\\.[A-Za-z0-9_\\-]+){0,2})",
+					Group:   1,
+				},
+			},
+			{
+				Name:       "slack_token",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretAPIKey,
+				Ruleset:    RulesetAPIKeys,
+				Regex: &RegexRule{
+					Pattern: "\\b(xox[abeoprs]-[0-9A-Za-z][0-9A-Za-z\\-]{8,})\\b",
+					Group:   1,
+				},
+			},
+			{
+				Name:       "slack_webhook",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretAPIKey,
+				Ruleset:    RulesetAPIKeys,
+				Regex: &RegexRule{
+					Pattern: "\\bhooks\\.slack\\.com/services/T[A-Z0-9]+/B[A-Z0-9]+/([A-Za-z0-9]{24})\\b",
+					Group:   1,
+				},
+			},
+			{
+				Name:       "openai_api_key",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretAPIKey,
+				Ruleset:    RulesetAPIKeys,
+				Regex: &RegexRule{
+					Pattern: "\\b(sk-(?:proj-|svcacct-|admin-)?[A-Za-z0-9_\\-]{20,}T3BlbkFJ[A-Za-z0-9_\\-]{20,})\\b",
+					Group:   1,
+				},
+			},
+			{
+				Name:       "anthropic_api_key",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretAPIKey,
+				Ruleset:    RulesetAPIKeys,
+				Regex: &RegexRule{
+					Pattern: "\\b(sk-ant-(?:api|admin)[0-9]{2}-[A-Za-z0-9_\\-]{80,})",
+					Group:   1,
+				},
+			},
+			{
+				Name:       "pypi_token",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretAPIKey,
+				Ruleset:    RulesetAPIKeys,
+				Regex: &RegexRule{
+					Pattern: "\\b(pypi-AgE[A-Za-z0-9_\\-]{50,})",
+					Group:   1,
+				},
+			},
+			{
+				Name:       "sendgrid_api_key",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretAPIKey,
+				Ruleset:    RulesetAPIKeys,
+				Regex: &RegexRule{
+					Pattern: "\\b(SG\\.[A-Za-z0-9_\\-]{22}\\.[A-Za-z0-9_\\-]{43})\\b",
+					Group:   1,
+				},
+			},
+			{
+				Name:       "twilio_auth_token",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretAPIKey,
+				Ruleset:    RulesetAPIKeys,
+				Regex: &RegexRule{
+					Pattern: "(?i)\\bTWILIO_(?:AUTH_TOKEN|API_SECRET)\\b\\s*[:=]\\s*[\"']?([A-Za-z0-9]{32})\\b",
+					Group:   1,
+				},
+			},
 			{
 				Name:       "bearer_token",
 				Enabled:    true,
@@ -295,7 +451,7 @@ func DefaultConfig() Config {
 				Action:     types.ActionMask,
 				Severity:   types.SeverityHigh,
 				SecretType: types.SecretAuthToken,
-				Ruleset:    "auth_tokens",
+				Ruleset:    RulesetAuthTokens,
 				Regex: &RegexRule{
 					Pattern: "(?i)\\bBearer\\s+([A-Za-z0-9\\-._~+/]{20,}={0,2})",
 					Group:   1,
@@ -308,7 +464,7 @@ func DefaultConfig() Config {
 				Action:     types.ActionMask,
 				Severity:   types.SeverityHigh,
 				SecretType: types.SecretAuthToken,
-				Ruleset:    "auth_tokens",
+				Ruleset:    RulesetAuthTokens,
 				Regex: &RegexRule{
 					Pattern: "(?i)\\b(access|refresh|auth)[_-]?token\\b\\s*[:=]\\s*([^\\s]+)",
 					Group:   2,
@@ -322,7 +478,7 @@ func DefaultConfig() Config {
 				Action:     types.ActionMask,
 				Severity:   types.SeverityHigh,
 				SecretType: types.SecretJWT,
-				Ruleset:    "auth_tokens",
+				Ruleset:    RulesetAuthTokens,
 				Regex: &RegexRule{
 					Pattern: "\\b(eyJ[A-Za-z0-9_-]+\\.[A-Za-z0-9_-]+\\.[A-Za-z0-9_-]+)\\b",
 					Group:   1,
@@ -335,9 +491,9 @@ func DefaultConfig() Config {
 				Action:     types.ActionMask,
 				Severity:   types.SeverityHigh,
 				SecretType: types.SecretCloudCred,
-				Ruleset:    "cloud",
+				Ruleset:    RulesetCloud,
 				Regex: &RegexRule{
-					Pattern: "\\bAKIA[0-9A-Z]{16}\\b",
+					Pattern: "\\b(?:AKIA|ASIA)[0-9A-Z]{16}\\b",
 					Group:   0,
 				},
 			},
@@ -348,12 +504,129 @@ func DefaultConfig() Config {
 				Action:     types.ActionMask,
 				Severity:   types.SeverityHigh,
 				SecretType: types.SecretCloudCred,
-				Ruleset:    "cloud",
+				Ruleset:    RulesetCloud,
 				Regex: &RegexRule{
-					Pattern: "(?i)\\baws_secret_access_key\\b\\s*[:=]\\s*([A-Za-z0-9/+=]{40})",
+					Pattern: "(?i)(?:\\baws_secret_access_key\\b|\"SecretAccessKey\")\\s*[:=]\\s*\"?([A-Za-z0-9/+=]{40})",
+					Group:   1,
+				},
+				ContextKeywords: []string{"aws", "secret_access_key", "secretaccesskey"},
+			},
+			{
+				Name:       "aws_session_token",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretCloudCred,
+				Ruleset:    RulesetCloud,
+				Regex: &RegexRule{
+					Pattern: "(?i)(?:\\baws_session_token\\b|\"SessionToken\")\\s*[:=]\\s*\"?([A-Za-z0-9/+=]{100,})",
+					Group:   1,
+				},
+			},
+			{
+				Name:       "gcp_private_key_id",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretCloudCred,
+				Ruleset:    RulesetCloud,
+				Regex: &RegexRule{
+					Pattern: "\"private_key_id\"\\s*:\\s*\"([0-9a-f]{40})\"",
+					Group:   1,
+				},
+			},
+			{
+				Name:       "gcp_api_key",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretCloudCred,
+				Ruleset:    RulesetCloud,
+				Regex: &RegexRule{
+					Pattern: "\\b(AIza[0-9A-Za-z_\\-]{35})(?:[^0-9A-Za-z_\\-]|$)",
+					Group:   1,
+				},
+			},
+			{
+				Name:       "azure_storage_account_key",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretCloudCred,
+				Ruleset:    RulesetCloud,
+				Regex: &RegexRule{
+					Pattern: "(?i)\\bAccountKey=([A-Za-z0-9+/]{86}==)",
+					Group:   1,
+				},
+			},
+			{
+				Name:       "azure_client_secret",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretCloudCred,
+				Ruleset:    RulesetCloud,
+				Regex: &RegexRule{
+					Pattern: "(?:^|[\\s'\"=:,(])([A-Za-z0-9_~.]{3}[0-9]Q~[A-Za-z0-9_~.\\-]{31,34})(?:[\\s'\",;)]|$)",
+					Group:   1,
+				},
+			},
+			{
+				Name:       "digitalocean_token",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretCloudCred,
+				Ruleset:    RulesetCloud,
+				Regex: &RegexRule{
+					Pattern: "\\b(do[opr]_v1_[a-f0-9]{64})\\b",
+					Group:   1,
+				},
+			},
+			{
+				Name:       "heroku_api_key",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretCloudCred,
+				Ruleset:    RulesetCloud,
+				Regex: &RegexRule{
+					Pattern: "\\b(HRKU-[A-Za-z0-9_\\-]{60})\\b",
+					Group:   1,
+				},
+			},
+			{
+				Name:       "cloudflare_api_token",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretCloudCred,
+				Ruleset:    RulesetCloud,
+				Regex: &RegexRule{
+					Pattern: "(?i)\\b(?:CLOUDFLARE|CF)_API_TOKEN\\b\\s*[:=]\\s*[\"']?([A-Za-z0-9_\\-]{40})\\b",
+					Group:   1,
+				},
+			},
+			{
+				Name:       "vercel_token",
+				Enabled:    true,
+				Type:       RuleTypeRegex,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretCloudCred,
+				Ruleset:    RulesetCloud,
+				Regex: &RegexRule{
+					Pattern: "(?i)(?:\\bVERCEL_(?:API_)?TOKEN\\b\\s*[:=]\\s*[\"']?|\\bvercel\\b[^\\n]*?--token[= ]\\s*)([A-Za-z0-9]{24})\\b",
 					Group:   1,
 				},
-				ContextKeywords: []string{"aws", "secret_access_key"},
 			},
 			{
 				Name:       "password_label",
@@ -362,7 +635,7 @@ func DefaultConfig() Config {
 				Action:     types.ActionMask,
 				Severity:   types.SeverityHigh,
 				SecretType: types.SecretPassword,
-				Ruleset:    "passwords",
+				Ruleset:    RulesetPasswords,
 				Regex: &RegexRule{
 					Pattern: "(?i)\\b(password|passwd|pwd|passphrase)\\b\\s*[:=]\\s*([^\\s]+)",
 					Group:   2,
@@ -374,13 +647,97 @@ func DefaultConfig() Config {
 			{
 				Name:            "evm_private_key",
 				Enabled:         true,
-				Kind:            "EVM_PRIVATE_KEY",
+				Kind:            TypedKindEvmPrivateKey,
 				Action:          types.ActionMask,
 				Severity:        types.SeverityHigh,
 				SecretType:      types.SecretEvmPrivateKey,
-				Ruleset:         "web3",
+				Ruleset:         RulesetWeb3,
 				ContextKeywords: []string{"private_key", "--private-key", "secret", "sk="},
 			},
+			{
+				Name:            "bip39_mnemonic",
+				Enabled:         true,
+				Kind:            TypedKindBip39Mnemonic,
+				Action:          types.ActionMask,
+				Severity:        types.SeverityHigh,
+				SecretType:      types.SecretSeedPhrase,
+				Ruleset:         RulesetWeb3,
+				ContextKeywords: []string{"mnemonic", "seed", "recovery", "phrase", "wallet"},
+			},
+			{
+				Name:            "extended_private_key",
+				Enabled:         true,
+				Kind:            TypedKindExtendedKey,
+				Action:          types.ActionMask,
+				Severity:        types.SeverityHigh,
+				SecretType:      types.SecretExtendedKey,
+				Ruleset:         RulesetWeb3,
+				ContextKeywords: []string{"xprv", "extended", "derivation", "bip32"},
+			},
+			{
+				Name:            "wif_private_key",
+				Enabled:         true,
+				Kind:            TypedKindWIFKey,
+				Action:          types.ActionMask,
+				Severity:        types.SeverityHigh,
+				SecretType:      types.SecretWIFKey,
+				Ruleset:         RulesetWeb3,
+				ContextKeywords: []string{"wif", "privkey", "dumpprivkey", "private key"},
+			},
+			{
+				Name:       "private_key_block",
+				Enabled:    true,
+				Kind:       TypedKindPrivateKeyBlock,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretPrivateKey,
+			},
+			{
+				Name:       "github_token",
+				Enabled:    true,
+				Kind:       TypedKindGithubToken,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretAPIKey,
+				Ruleset:    RulesetAPIKeys,
+			},
+			{
+				Name:       "npm_token",
+				Enabled:    true,
+				Kind:       TypedKindNpmToken,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretAPIKey,
+				Ruleset:    RulesetAPIKeys,
+			},
+			{
+				Name:       "connection_string_password",
+				Enabled:    true,
+				Kind:       TypedKindConnectionString,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityHigh,
+				SecretType: types.SecretPassword,
+				Ruleset:    RulesetConnStrings,
+			},
+			{
+				Name:       "structured_value",
+				Enabled:    true,
+				Kind:       TypedKindStructuredValue,
+				Action:     types.ActionMask,
+				Severity:   types.SeverityLow,
+				SecretType: types.SecretGeneric,
+				Ruleset:    RulesetStructured,
+			},
+			{
+				Name:            "high_entropy_string",
+				Enabled:         true,
+				Kind:            TypedKindHighEntropy,
+				Action:          types.ActionMask,
+				Severity:        types.SeverityMed,
+				SecretType:      types.SecretGeneric,
+				Ruleset:         RulesetEntropy,
+				ContextKeywords: []string{"secret", "token", "key", "password", "passwd", "credential", "auth"},
+			},
 		},
 		UI: UI{
 			ShellBanner: false,
@@ -392,24 +749,17 @@ func DefaultConfig() Config {
 	}
 }
 
-// RulesetEnabled reports whether a ruleset is active.
-func RulesetEnabled(name string, sets Rulesets) bool {
-	switch name {
-	case "":
-		return true
-	case "web3":
-		return sets.Web3.Enabled
-	case "api_keys":
-		return sets.APIKeys.Enabled
-	case "auth_tokens":
-		return sets.AuthTokens.Enabled
-	case "cloud":
-		return sets.Cloud.Enabled
-	case "passwords":
-		return sets.Passwords.Enabled
-	default:
-		return false
-	}
+// LockedUntilExit reports whether strict policy forbids pausing redaction or
+// copying originals for the rest of a session.
+func (c Config) LockedUntilExit() bool {
+	return c.Mode == types.ModeStrict && c.Strict.LockUntilExit
+}
+
+// NoRevealEnforced reports whether strict policy masks matches that would
+// otherwise be rendered with a warning. It does not affect bypasses the user
+// configured, such as the allowlist; see LockedUntilExit.
+func (c Config) NoRevealEnforced() bool {
+	return c.Mode == types.ModeStrict && c.Strict.NoReveal
 }
 
 // DefaultPath returns the default config path.
@@ -424,14 +774,71 @@ func DefaultPath() (string, error) {
 	return filepath.Join(home, ".config", defaultConfigRelPath), nil
 }
 
-// Parse parses YAML config content, applying defaults.
+// ExpandHome replaces a leading ~ with the user's home directory.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// Parse parses YAML config content, applying defaults. Relative include
+// paths resolve against the working directory.
 func Parse(data []byte) (Config, error) {
+	return parse(data, "")
+}
+
+// parse decodes a config file at path, resolves its includes and validates
+// the result. An empty path resolves includes against the working directory.
+func parse(data []byte, path string) (Config, error) {
+	var head struct {
+		Include        []string  `yaml:"include"`
+		Rules          yaml.Node `yaml:"rules"`
+		TypedDetectors yaml.Node `yaml:"typed_detectors"`
+	}
+	if err := yaml.Unmarshal(data, &head); err != nil {
+		return Config{}, fmt.Errorf("parse config: %w", err)
+	}
 	cfg := DefaultConfig()
+	packs, problems, err := loadRulePacks(head.Include, filepath.Dir(path))
+	if err != nil {
+		return Config{}, err
+	}
+	// Pack rulesets layer over the defaults so the config file can still
+	// override them.
+	for _, pack := range packs {
+		if err := pack.applyRulesets(&cfg); err != nil {
+			return Config{}, err
+		}
+	}
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return Config{}, fmt.Errorf("parse config: %w", err)
 	}
-	if err := cfg.Validate(); err != nil {
-		return Config{}, err
+	if path != "" {
+		if head.Rules.Kind != 0 {
+			setRuleSource(cfg.Rules, path)
+		}
+		if head.TypedDetectors.Kind != 0 {
+			setDetectorSource(cfg.TypedDetectors, path)
+		}
+	}
+
+	problems = append(problems, cfg.problems()...)
+	if len(problems) > 0 {
+		var root yaml.Node
+		if err := yaml.Unmarshal(data, &root); err == nil {
+			for i, problem := range problems {
+				problems[i] = annotatePosition(&root, problem)
+			}
+		}
+	}
+	problems = append(problems, mergeRulePacks(&cfg, packs)...)
+	if len(problems) > 0 {
+		return Config{}, invalidConfigError(problems)
 	}
 	return cfg, nil
 }
@@ -459,7 +866,7 @@ func Load(pathOverride string) (Config, bool, error) {
 		}
 		return Config{}, false, fmt.Errorf("read config: %w", err)
 	}
-	cfg, err := Parse(data)
+	cfg, err := parse(data, path)
 	if err != nil {
 		return Config{}, true, err
 	}
@@ -468,6 +875,19 @@ func Load(pathOverride string) (Config, bool, error) {
 
 // Validate enforces the supported configuration schema.
 func (c Config) Validate() error {
+	return invalidConfigError(c.problems())
+}
+
+func invalidConfigError(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidConfig, strings.Join(problems, "; "))
+}
+
+// problems lists schema violations, each prefixed with the offending field
+// path.
+func (c Config) problems() []string {
 	var errs []string
 	if c.Version != DefaultConfigVersion {
 		errs = append(errs, fmt.Sprintf("version must be %d", DefaultConfigVersion))
@@ -476,7 +896,7 @@ func (c Config) Validate() error {
 		errs = append(errs, fmt.Sprintf("mode must be one of: %s", strings.Join(validModes(), ", ")))
 	}
 	if !validAction(c.Redaction.DefaultAction) {
-		errs = append(errs, "redaction.default_action must be mask or placeholder")
+		errs = append(errs, "redaction.default_action must be mask, placeholder, or warn")
 	}
 	if c.Redaction.PlaceholderTemplate == "" {
 		errs = append(errs, "redaction.placeholder_template is required")
@@ -484,9 +904,15 @@ func (c Config) Validate() error {
 	if c.Redaction.RollingWindowBytes < 0 {
 		errs = append(errs, "redaction.rolling_window_bytes must be >= 0")
 	}
+	if c.Redaction.BlockMaxBytes <= 0 {
+		errs = append(errs, "redaction.block_max_bytes must be > 0")
+	}
 	if c.Redaction.StatusLine.RateLimitMS < 0 {
 		errs = append(errs, "redaction.status_line.rate_limit_ms must be >= 0")
 	}
+	if !validWarnHighlight(c.Redaction.Warn.Highlight) {
+		errs = append(errs, "redaction.warn.highlight must be reverse|underline|bold")
+	}
 	if c.Masking.BlockChar == "" {
 		errs = append(errs, "masking.block_char is required")
 	}
@@ -504,6 +930,17 @@ func (c Config) Validate() error {
 	} else if !validClipboardBackend(c.Overrides.CopyWithoutRender.Backend) {
 		errs = append(errs, "overrides.copy_without_render.backend must be one of: auto, pbcopy, wl-copy, xclip, xsel, none")
 	}
+	if c.Transcript.MaxBytes < 0 {
+		errs = append(errs, "transcript.max_bytes must be >= 0")
+	}
+	errs = append(errs, c.Rulesets.problems()...)
+	errs = append(errs, c.KnownSecrets.problems()...)
+	if c.Decode.MaxDepth < 1 || c.Decode.MaxDepth > 4 {
+		errs = append(errs, "decode.max_depth must be between 1 and 4")
+	}
+	if c.Decode.MaxSpanBytes <= 0 {
+		errs = append(errs, "decode.max_span_bytes must be > 0")
+	}
 	for i, entry := range c.Allowlist.Commands {
 		trimmed := strings.TrimSpace(entry)
 		if trimmed == "" {
@@ -514,56 +951,104 @@ func (c Config) Validate() error {
 			errs = append(errs, fmt.Sprintf("allowlist.commands[%d] has invalid pattern: %v", i, err))
 		}
 	}
-	for i, rule := range c.Rules {
+	for i, dir := range c.TrustedOverlays {
+		if !filepath.IsAbs(ExpandHome(strings.TrimSpace(dir))) {
+			errs = append(errs, fmt.Sprintf("trusted_overlays[%d] must be an absolute path", i))
+		}
+	}
+	errs = append(errs, c.policyProblems()...)
+	errs = append(errs, c.ruleProblems(c.Rules, c.TypedDetectors)...)
+	return errs
+}
+
+// ruleProblems checks rules and typed detectors against the config's
+// rulesets and registered kinds.
+func (c Config) ruleProblems(rules []Rule, dets []TypedDetector) []string {
+	var errs []string
+	for i, rule := range rules {
 		if rule.Name == "" {
 			errs = append(errs, fmt.Sprintf("rules[%d].name is required", i))
 		}
 		if !validRuleType(rule.Type) {
 			errs = append(errs, fmt.Sprintf("rules[%d].type must be regex or typed", i))
 		}
-		if !validAction(rule.Action) {
-			errs = append(errs, fmt.Sprintf("rules[%d].action must be mask or placeholder", i))
-		}
-		if !validSeverity(rule.Severity) {
-			errs = append(errs, fmt.Sprintf("rules[%d].severity must be low|med|high", i))
-		}
+		errs = append(errs, c.memberProblems(fmt.Sprintf("rules[%d]", i), rule.Ruleset, rule.Action, rule.Severity)...)
 		if rule.Type == RuleTypeRegex {
 			if rule.Regex == nil {
 				errs = append(errs, fmt.Sprintf("rules[%d].regex is required for regex rule", i))
 			} else {
-				if rule.Regex.Pattern == "" {
-					errs = append(errs, fmt.Sprintf("rules[%d].regex.pattern is required", i))
-				}
-				if rule.Regex.Group < 0 {
-					errs = append(errs, fmt.Sprintf("rules[%d].regex.group must be >= 0", i))
-				}
+				errs = append(errs, regexProblems(i, rule.Regex)...)
 			}
 		}
-		if rule.Ruleset != "" && !validRuleset(rule.Ruleset) {
-			errs = append(errs, fmt.Sprintf("rules[%d].ruleset is not supported", i))
-		}
 	}
-	for i, det := range c.TypedDetectors {
+	for i, det := range dets {
 		if det.Name == "" {
 			errs = append(errs, fmt.Sprintf("typed_detectors[%d].name is required", i))
 		}
 		if det.Kind == "" {
 			errs = append(errs, fmt.Sprintf("typed_detectors[%d].kind is required", i))
+		} else if !validTypedKind(det.Kind) {
+			errs = append(errs, fmt.Sprintf("typed_detectors[%d].kind must be one of: %s", i, strings.Join(TypedKinds(), ", ")))
 		}
-		if !validAction(det.Action) {
-			errs = append(errs, fmt.Sprintf("typed_detectors[%d].action must be mask or placeholder", i))
-		}
-		if !validSeverity(det.Severity) {
-			errs = append(errs, fmt.Sprintf("typed_detectors[%d].severity must be low|med|high", i))
-		}
-		if det.Ruleset != "" && !validRuleset(det.Ruleset) {
-			errs = append(errs, fmt.Sprintf("typed_detectors[%d].ruleset is not supported", i))
+		errs = append(errs, c.memberProblems(fmt.Sprintf("typed_detectors[%d]", i), det.Ruleset, det.Action, det.Severity)...)
+	}
+	return errs
+}
+
+func regexProblems(i int, spec *RegexRule) []string {
+	if spec.Pattern == "" {
+		return []string{fmt.Sprintf("rules[%d].regex.pattern is required", i)}
+	}
+	var errs []string
+	re, err := regexp.Compile(spec.Pattern)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("rules[%d].regex.pattern is invalid: %v", i, err))
+	}
+	if spec.Group < 0 {
+		errs = append(errs, fmt.Sprintf("rules[%d].regex.group must be >= 0", i))
+	} else if re != nil && spec.Group > re.NumSubexp() {
+		errs = append(errs, fmt.Sprintf("rules[%d].regex.group %d exceeds the pattern's %d capture groups", i, spec.Group, re.NumSubexp()))
+	}
+	return errs
+}
+
+func (k KnownSecrets) problems() []string {
+	var errs []string
+	if k.MinLength < 4 {
+		errs = append(errs, "known_secrets.min_length must be >= 4")
+	}
+	if !validAction(k.Action) {
+		errs = append(errs, "known_secrets.action must be mask, placeholder, or warn")
+	}
+	lists := []struct {
+		field   string
+		entries []string
+	}{
+		{"known_secrets.env", k.Env},
+		{"known_secrets.env_files", k.EnvFiles},
+		{"known_secrets.files", k.Files},
+	}
+	for _, list := range lists {
+		for i, entry := range list.entries {
+			if strings.TrimSpace(entry) == "" {
+				errs = append(errs, fmt.Sprintf("%s[%d] must not be empty", list.field, i))
+			}
 		}
 	}
-	if len(errs) > 0 {
-		return fmt.Errorf("%w: %s", ErrInvalidConfig, strings.Join(errs, "; "))
+	return errs
+}
+
+// entropyThresholdProblems checks a threshold against the charset's maximum
+// entropy in bits per character.
+func entropyThresholdProblems(field string, t EntropyThreshold, maxBits float64) []string {
+	var errs []string
+	if t.MinLength < 8 {
+		errs = append(errs, field+".min_length must be >= 8")
+	}
+	if t.MinEntropy <= 0 || t.MinEntropy > maxBits {
+		errs = append(errs, fmt.Sprintf("%s.min_entropy must be > 0 and <= %g", field, maxBits))
 	}
-	return nil
+	return errs
 }
 
 func validMode(mode types.Mode) bool {
@@ -581,7 +1066,7 @@ func validModes() []string {
 
 func validAction(action types.Action) bool {
 	switch action {
-	case types.ActionMask, types.ActionPlaceholder:
+	case types.ActionMask, types.ActionPlaceholder, types.ActionWarn:
 		return true
 	default:
 		return false
@@ -597,27 +1082,27 @@ func validMaskStyle(style types.MaskStyle) bool {
 	}
 }
 
-func validSeverity(severity types.Severity) bool {
-	switch severity {
-	case types.SeverityLow, types.SeverityMed, types.SeverityHigh:
+func validWarnHighlight(highlight types.WarnHighlight) bool {
+	switch highlight {
+	case types.WarnHighlightReverse, types.WarnHighlightUnderline, types.WarnHighlightBold:
 		return true
 	default:
 		return false
 	}
 }
 
-func validRuleType(ruleType RuleType) bool {
-	switch ruleType {
-	case RuleTypeRegex, RuleTypeTyped:
+func validSeverity(severity types.Severity) bool {
+	switch severity {
+	case types.SeverityLow, types.SeverityMed, types.SeverityHigh:
 		return true
 	default:
 		return false
 	}
 }
 
-func validRuleset(name string) bool {
-	switch name {
-	case "web3", "api_keys", "auth_tokens", "cloud", "passwords":
+func validRuleType(ruleType RuleType) bool {
+	switch ruleType {
+	case RuleTypeRegex, RuleTypeTyped:
 		return true
 	default:
 		return false
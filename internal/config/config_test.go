@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/suryansh-23/secretty/internal/types"
@@ -44,10 +46,10 @@ func TestParseCanonicalConfig(t *testing.T) {
 	if cfg.UI.ShellBanner {
 		t.Fatalf("ui.shell_banner = true, want false")
 	}
-	if len(cfg.Rules) != 10 {
+	if len(cfg.Rules) != 28 {
 		t.Fatalf("rules count = %d", len(cfg.Rules))
 	}
-	if len(cfg.TypedDetectors) != 1 {
+	if len(cfg.TypedDetectors) != 10 {
 		t.Fatalf("typed_detectors count = %d", len(cfg.TypedDetectors))
 	}
 }
@@ -88,3 +90,165 @@ func TestValidationRejectsInvalidAllowlistEntry(t *testing.T) {
 		t.Fatalf("expected validation error for invalid pattern")
 	}
 }
+
+func TestValidationAcceptsWarnAction(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Rules[0].Action = types.ActionWarn
+	cfg.TypedDetectors[0].Action = types.ActionWarn
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected warn action to validate: %v", err)
+	}
+	cfg.Redaction.Warn.Highlight = "blink"
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected validation error for unknown warn highlight")
+	}
+}
+
+func TestParseReportsRegexErrorPosition(t *testing.T) {
+	data := []byte(`version: 1
+rules:
+  - name: broken
+    enabled: true
+    type: regex
+    action: mask
+    severity: high
+    regex:
+      pattern: "token=([a-z"
+      group: 1
+  - name: bad_group
+    enabled: true
+    type: regex
+    action: mask
+    severity: high
+    regex:
+      pattern: "key=[a-z]+"
+      group: 2
+`)
+	_, err := Parse(data)
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected invalid config, got %v", err)
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "rules[0].regex.pattern is invalid") || !strings.Contains(msg, "(line 9, column 16)") {
+		t.Fatalf("expected pattern error with position, got %q", msg)
+	}
+	if !strings.Contains(msg, "rules[1].regex.group 2 exceeds the pattern's 0 capture groups (line 18, column 14)") {
+		t.Fatalf("expected group error with position, got %q", msg)
+	}
+}
+
+func TestParseReportsMissingFieldAtEnclosingNode(t *testing.T) {
+	data := []byte("version: 1\nrules:\n  - enabled: true\n    type: regex\n    action: mask\n    severity: high\n    regex:\n      pattern: \"x\"\n")
+	_, err := Parse(data)
+	if err == nil || !strings.Contains(err.Error(), "rules[0].name is required (line 3, column 5)") {
+		t.Fatalf("expected name error at rule position, got %v", err)
+	}
+}
+
+func TestValidationRejectsUnknownTypedKind(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TypedDetectors[0].Kind = "SOLANA_KEY"
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "typed_detectors[0].kind must be one of: BIP39_MNEMONIC, CONNECTION_STRING, EVM_PRIVATE_KEY, EXTENDED_PRIVATE_KEY, GITHUB_TOKEN, HIGH_ENTROPY, NPM_TOKEN, PRIVATE_KEY_BLOCK, STRUCTURED_VALUE, WIF_PRIVATE_KEY") {
+		t.Fatalf("expected unknown kind error, got %v", err)
+	}
+}
+
+func TestValidationRejectsEntropyThresholds(t *testing.T) {
+	cfg := DefaultConfig()
+	entropy := cfg.Rulesets[RulesetEntropy]
+	entropy.Hex.MinEntropy = 5
+	entropy.Base64.MinLength = 4
+	cfg.Rulesets[RulesetEntropy] = entropy
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "rulesets.entropy.hex.min_entropy") || !strings.Contains(err.Error(), "rulesets.entropy.base64.min_length") {
+		t.Fatalf("expected entropy threshold errors, got %v", err)
+	}
+}
+
+func TestParseStructuredKeys(t *testing.T) {
+	cfg, err := Parse([]byte("version: 1\nrulesets:\n  structured:\n    enabled: true\n    keys: [\"license_*\"]\n"))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	set := cfg.Rulesets[RulesetStructured]
+	if !set.Enabled || len(set.Keys) != 1 || set.Description == "" {
+		t.Fatalf("expected keys replaced and description kept, got %+v", set)
+	}
+
+	_, err = Parse([]byte("version: 1\nrulesets:\n  structured:\n    keys: [\"[bad\", \"\"]\n"))
+	if err == nil || !strings.Contains(err.Error(), `rulesets.structured.keys[0] "[bad" is not a valid glob`) || !strings.Contains(err.Error(), "rulesets.structured.keys[1] must not be empty") {
+		t.Fatalf("expected key glob errors, got %v", err)
+	}
+}
+
+func TestParseCustomRulesetInheritsActionAndSeverity(t *testing.T) {
+	data := []byte(`version: 1
+rulesets:
+  acme_internal:
+    enabled: true
+    description: "ACME service tokens"
+    action: placeholder
+    severity: high
+  entropy:
+    enabled: true
+rules:
+  - name: acme_service_token
+    enabled: true
+    type: regex
+    secret_type: API_KEY
+    ruleset: acme_internal
+    regex:
+      pattern: "acme_[a-z0-9]{8}"
+`)
+	cfg, err := Parse(data)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !RulesetEnabled("acme_internal", cfg.Rulesets) {
+		t.Fatalf("expected acme_internal to be enabled")
+	}
+	rule := cfg.EffectiveRule(cfg.Rules[0])
+	if rule.Action != types.ActionPlaceholder || rule.Severity != types.SeverityHigh {
+		t.Fatalf("effective rule = %s/%s, want placeholder/high", rule.Action, rule.Severity)
+	}
+	entropy := cfg.Rulesets[RulesetEntropy]
+	if !entropy.Enabled || entropy.Hex.MinLength != 32 || entropy.Base64.MinEntropy != 4.0 {
+		t.Fatalf("expected partial override to keep default thresholds, got %+v", entropy)
+	}
+	if !cfg.Rulesets[RulesetWeb3].Enabled {
+		t.Fatalf("expected built-in rulesets to survive a custom entry")
+	}
+}
+
+func TestValidationRejectsUndefinedRuleset(t *testing.T) {
+	data := []byte(`version: 1
+rules:
+  - name: acme_service_token
+    enabled: true
+    type: regex
+    action: mask
+    severity: high
+    ruleset: acme_internal
+    regex:
+      pattern: "acme_[a-z0-9]{8}"
+`)
+	_, err := Parse(data)
+	if err == nil || !strings.Contains(err.Error(), `rules[0].ruleset "acme_internal" is not defined under rulesets (line 8, column 14)`) {
+		t.Fatalf("expected undefined ruleset error, got %v", err)
+	}
+}
+
+func TestValidationRequiresSeverityWithoutRulesetDefault(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Rules[0].Severity = ""
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "rules[0].severity must be low|med|high") {
+		t.Fatalf("expected severity error, got %v", err)
+	}
+	web3 := cfg.Rulesets[RulesetWeb3]
+	web3.Severity = types.SeverityHigh
+	cfg.Rulesets[RulesetWeb3] = web3
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected inherited severity to validate: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"slices"
+	"sync"
+)
+
+// Typed detector kinds implemented by the detection engine.
+const (
+	TypedKindEvmPrivateKey    = "EVM_PRIVATE_KEY"
+	TypedKindBip39Mnemonic    = "BIP39_MNEMONIC"
+	TypedKindExtendedKey      = "EXTENDED_PRIVATE_KEY"
+	TypedKindWIFKey           = "WIF_PRIVATE_KEY"
+	TypedKindHighEntropy      = "HIGH_ENTROPY"
+	TypedKindPrivateKeyBlock  = "PRIVATE_KEY_BLOCK"
+	TypedKindConnectionString = "CONNECTION_STRING"
+	TypedKindGithubToken      = "GITHUB_TOKEN"
+	TypedKindNpmToken         = "NPM_TOKEN"
+	TypedKindStructuredValue  = "STRUCTURED_VALUE"
+)
+
+// builtinKinds lists every kind the detection engine implements, so config
+// validates on its own. Kinds added outside the engine's built-ins are
+// registered with RegisterTypedKind.
+var builtinKinds = []string{
+	TypedKindBip39Mnemonic,
+	TypedKindConnectionString,
+	TypedKindEvmPrivateKey,
+	TypedKindExtendedKey,
+	TypedKindGithubToken,
+	TypedKindHighEntropy,
+	TypedKindNpmToken,
+	TypedKindPrivateKeyBlock,
+	TypedKindStructuredValue,
+	TypedKindWIFKey,
+}
+
+var (
+	extraKindsMu sync.RWMutex
+	extraKinds   []string
+)
+
+// RegisterTypedKind makes kind valid in typed_detectors. The detection
+// engine registers each kind it adds beyond the built-ins.
+func RegisterTypedKind(kind string) {
+	extraKindsMu.Lock()
+	defer extraKindsMu.Unlock()
+	if !slices.Contains(builtinKinds, kind) && !slices.Contains(extraKinds, kind) {
+		extraKinds = append(extraKinds, kind)
+	}
+}
+
+// TypedKinds returns the valid typed detector kinds, sorted.
+func TypedKinds() []string {
+	extraKindsMu.RLock()
+	defer extraKindsMu.RUnlock()
+	kinds := slices.Concat(builtinKinds, extraKinds)
+	slices.Sort(kinds)
+	return kinds
+}
+
+func validTypedKind(kind string) bool {
+	return slices.Contains(TypedKinds(), kind)
+}
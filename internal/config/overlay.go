@@ -0,0 +1,207 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+// ProjectFileName is the per-project overlay discovered from a session's
+// working directory.
+const ProjectFileName = ".secretty.yaml"
+
+// FindOverlay returns the nearest ProjectFileName in dir or one of its
+// parents, or "" when there is none.
+func FindOverlay(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	dir = filepath.Clean(dir)
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// ApplyOverlay layers the project overlay at path over base. An overlay may
+// set mode, strict, allowlist, rulesets, rules, typed_detectors and include;
+// other keys are ignored. Its rules follow the same name rules as included
+// packs, and relative includes resolve against the overlay's directory.
+// Strict settings can only be tightened; see tighten. An overlay outside the
+// directories listed in base's trusted_overlays cannot loosen base at all: the
+// mode can only move toward strict, the allowlist and rulesets are protected
+// as in strict mode, and its rules mask instead of warning.
+func ApplyOverlay(base Config, path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("read overlay: %w", err)
+	}
+	var head struct {
+		Include []string `yaml:"include"`
+	}
+	if err := yaml.Unmarshal(data, &head); err != nil {
+		return Config{}, fmt.Errorf("parse overlay %s: %w", path, err)
+	}
+	self, err := loadRulePack(path)
+	if err != nil {
+		return Config{}, err
+	}
+	packs, problems, err := loadRulePacks(head.Include, filepath.Dir(path))
+	if err != nil {
+		return Config{}, err
+	}
+	packs = append(packs, self)
+
+	cfg := base
+	cfg.Rulesets = maps.Clone(base.Rulesets)
+	cfg.Rules = append([]Rule(nil), base.Rules...)
+	cfg.TypedDetectors = append([]TypedDetector(nil), base.TypedDetectors...)
+	cfg.Allowlist.Commands = append([]string(nil), base.Allowlist.Commands...)
+	for _, pack := range packs {
+		if err := pack.applyRulesets(&cfg); err != nil {
+			return Config{}, err
+		}
+	}
+	settings := struct {
+		Mode      types.Mode `yaml:"mode"`
+		Strict    Strict     `yaml:"strict"`
+		Allowlist Allowlist  `yaml:"allowlist"`
+	}{cfg.Mode, cfg.Strict, cfg.Allowlist}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return Config{}, fmt.Errorf("parse overlay %s: %w", path, err)
+	}
+	cfg.Mode, cfg.Strict, cfg.Allowlist = settings.Mode, settings.Strict, settings.Allowlist
+	tighten(base, &cfg)
+	if !base.TrustsOverlay(path) {
+		if validMode(cfg.Mode) && modeStrength(cfg.Mode) < modeStrength(base.Mode) {
+			cfg.Mode = base.Mode
+		}
+		protect(base, &cfg)
+	}
+
+	problems = append(problems, cfg.problems()...)
+	for i, problem := range problems {
+		problems[i] = path + ": " + annotatePosition(&self.root, problem)
+	}
+	problems = append(problems, mergeRulePacks(&cfg, packs)...)
+	if len(problems) > 0 {
+		return Config{}, invalidConfigError(problems)
+	}
+	if !base.TrustsOverlay(path) {
+		maskAdded(&cfg, len(base.Rules), len(base.TypedDetectors))
+	}
+	return cfg, nil
+}
+
+// maskAdded makes the rules and typed detectors after the first rules and
+// dets mask where they would warn, so an untrusted overlay cannot add a rule
+// that wins an overlap against a masking rule and leaves its secret visible.
+func maskAdded(cfg *Config, rules, dets int) {
+	warns := func(action types.Action) bool {
+		if action == "" {
+			action = cfg.Redaction.DefaultAction
+		}
+		return action == types.ActionWarn
+	}
+	for i := rules; i < len(cfg.Rules); i++ {
+		if warns(cfg.EffectiveRule(cfg.Rules[i]).Action) {
+			cfg.Rules[i].Action = types.ActionMask
+		}
+	}
+	for i := dets; i < len(cfg.TypedDetectors); i++ {
+		if warns(cfg.EffectiveDetector(cfg.TypedDetectors[i]).Action) {
+			cfg.TypedDetectors[i].Action = types.ActionMask
+		}
+	}
+}
+
+// tighten undoes any part of an overlay that would weaken base's strict
+// settings. Strict flags can be set but not cleared. When base is in strict
+// mode the mode stays strict, the allowlist stays as configured, and base
+// rulesets keep detecting at least as much as before: enabled stays enabled,
+// allow_bare_64hex stays set, a non-warn action is not turned into warn,
+// entropy thresholds can only be lowered and structured keys only added.
+func tighten(base Config, cfg *Config) {
+	cfg.Strict.NoReveal = cfg.Strict.NoReveal || base.Strict.NoReveal
+	cfg.Strict.LockUntilExit = cfg.Strict.LockUntilExit || base.Strict.LockUntilExit
+	cfg.Strict.DisableCopyOriginal = cfg.Strict.DisableCopyOriginal || base.Strict.DisableCopyOriginal
+	if base.Mode != types.ModeStrict {
+		return
+	}
+	cfg.Mode = types.ModeStrict
+	protect(base, cfg)
+}
+
+// protect keeps base's allowlist and keeps base rulesets detecting at least
+// as much as before.
+func protect(base Config, cfg *Config) {
+	cfg.Allowlist = base.Allowlist
+	for name, was := range base.Rulesets {
+		set := cfg.Rulesets[name]
+		set.Enabled = set.Enabled || was.Enabled
+		set.AllowBare64Hex = set.AllowBare64Hex || was.AllowBare64Hex
+		if set.Action == types.ActionWarn && was.Action != types.ActionWarn {
+			set.Action = was.Action
+		}
+		set.Hex = lowerThreshold(set.Hex, was.Hex)
+		set.Base64 = lowerThreshold(set.Base64, was.Base64)
+		set.Keys = unionKeys(was.Keys, set.Keys)
+		cfg.Rulesets[name] = set
+	}
+}
+
+// TrustsOverlay reports whether the overlay at path is in a directory listed
+// under trusted_overlays.
+func (c Config) TrustsOverlay(path string) bool {
+	dir := filepath.Dir(filepath.Clean(path))
+	for _, trusted := range c.TrustedOverlays {
+		if filepath.Clean(ExpandHome(strings.TrimSpace(trusted))) == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// modeStrength orders modes by how much they hide: warn, then demo, then
+// strict.
+func modeStrength(mode types.Mode) int {
+	switch mode {
+	case types.ModeStrict:
+		return 2
+	case types.ModeDemo:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func lowerThreshold(a, b EntropyThreshold) EntropyThreshold {
+	return EntropyThreshold{
+		MinLength:  min(a.MinLength, b.MinLength),
+		MinEntropy: min(a.MinEntropy, b.MinEntropy),
+	}
+}
+
+func unionKeys(base, extra []string) []string {
+	out := append([]string(nil), base...)
+	for _, key := range extra {
+		if !slices.Contains(out, key) {
+			out = append(out, key)
+		}
+	}
+	return out
+}
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+func TestFindOverlayWalksUp(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "repo", ProjectFileName), "mode: strict\n")
+	nested := filepath.Join(root, "repo", "cmd", "tool")
+	writeFile(t, filepath.Join(nested, "main.go"), "package main\n")
+	if got := FindOverlay(nested); got != filepath.Join(root, "repo", ProjectFileName) {
+		t.Fatalf("FindOverlay = %q", got)
+	}
+	if got := FindOverlay(root); got != "" {
+		t.Fatalf("expected no overlay above the repo, got %q", got)
+	}
+}
+
+func TestApplyOverlayLayersRulesAndRulesets(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "rules.yaml"), acmePack)
+	path := filepath.Join(dir, ProjectFileName)
+	writeFile(t, path, `include: [rules.yaml]
+rulesets:
+  web3:
+    allow_bare_64hex: true
+  entropy:
+    enabled: true
+allowlist:
+  enabled: true
+  commands: ["forge"]
+`)
+	base := DefaultConfig()
+	base.Mode = types.ModeDemo
+	base.TrustedOverlays = []string{dir}
+	cfg, err := ApplyOverlay(base, path)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if !cfg.Rulesets[RulesetWeb3].AllowBare64Hex || !cfg.Rulesets[RulesetWeb3].Enabled {
+		t.Fatalf("expected web3 to keep enabled and allow bare hex, got %+v", cfg.Rulesets[RulesetWeb3])
+	}
+	if !RulesetEnabled(RulesetEntropy, cfg.Rulesets) || !RulesetEnabled("acme_internal", cfg.Rulesets) {
+		t.Fatalf("expected overlay rulesets enabled")
+	}
+	if last := cfg.Rules[len(cfg.Rules)-1]; last.Name != "acme_service_token" {
+		t.Fatalf("expected included rule appended, got %s", last.Name)
+	}
+	if !cfg.Allowlist.Enabled || len(cfg.Allowlist.Commands) != 1 {
+		t.Fatalf("expected overlay allowlist, got %+v", cfg.Allowlist)
+	}
+	if base.Rulesets[RulesetWeb3].AllowBare64Hex || RulesetEnabled(RulesetEntropy, base.Rulesets) || len(base.Rules) == len(cfg.Rules) {
+		t.Fatalf("expected base config untouched")
+	}
+}
+
+func TestApplyOverlayCannotLoosenStrictMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ProjectFileName)
+	writeFile(t, path, `mode: demo
+strict:
+  no_reveal: false
+  disable_copy_original: true
+allowlist:
+  enabled: true
+  commands: ["cat"]
+rulesets:
+  web3:
+    enabled: false
+    action: warn
+  entropy:
+    hex:
+      min_length: 64
+      min_entropy: 2.5
+  structured:
+    keys: ["license_*"]
+`)
+	base := DefaultConfig()
+	cfg, err := ApplyOverlay(base, path)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if cfg.Mode != types.ModeStrict || !cfg.Strict.NoReveal || !cfg.Strict.DisableCopyOriginal {
+		t.Fatalf("expected strict settings kept or tightened, got mode=%s strict=%+v", cfg.Mode, cfg.Strict)
+	}
+	if cfg.Allowlist.Enabled {
+		t.Fatalf("expected overlay allowlist ignored in strict mode")
+	}
+	web3 := cfg.Rulesets[RulesetWeb3]
+	if !web3.Enabled || web3.Action == types.ActionWarn {
+		t.Fatalf("expected web3 to stay enabled and masking, got %+v", web3)
+	}
+	hex := cfg.Rulesets[RulesetEntropy].Hex
+	if hex.MinLength != 32 || hex.MinEntropy != 2.5 {
+		t.Fatalf("expected entropy thresholds only lowered, got %+v", hex)
+	}
+	keys := cfg.Rulesets[RulesetStructured].Keys
+	if len(keys) != len(DefaultStructuredKeys())+1 || keys[0] != "password" || keys[len(keys)-1] != "license_*" {
+		t.Fatalf("expected structured keys only added, got %v", keys)
+	}
+}
+
+func TestUntrustedOverlayCannotLoosenConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ProjectFileName)
+	writeFile(t, path, `mode: warn
+allowlist:
+  enabled: true
+  commands: ["cat"]
+rulesets:
+  web3:
+    enabled: false
+  entropy:
+    enabled: true
+`)
+	base := DefaultConfig()
+	base.Mode = types.ModeDemo
+	cfg, err := ApplyOverlay(base, path)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if cfg.Mode != types.ModeDemo || cfg.Allowlist.Enabled || !RulesetEnabled(RulesetWeb3, cfg.Rulesets) {
+		t.Fatalf("expected untrusted overlay not to loosen, got mode=%s allowlist=%+v web3=%+v", cfg.Mode, cfg.Allowlist, cfg.Rulesets[RulesetWeb3])
+	}
+	if !RulesetEnabled(RulesetEntropy, cfg.Rulesets) {
+		t.Fatalf("expected untrusted overlay to tighten")
+	}
+
+	base.TrustedOverlays = []string{dir}
+	if cfg, err = ApplyOverlay(base, path); err != nil {
+		t.Fatalf("apply trusted: %v", err)
+	}
+	if cfg.Mode != types.ModeWarn || !cfg.Allowlist.Enabled || RulesetEnabled(RulesetWeb3, cfg.Rulesets) {
+		t.Fatalf("expected trusted overlay to apply, got mode=%s allowlist=%+v", cfg.Mode, cfg.Allowlist)
+	}
+}
+
+func TestApplyOverlayReportsProblemsWithPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ProjectFileName)
+	writeFile(t, path, "mode: loud\n")
+	base := DefaultConfig()
+	base.Mode = types.ModeDemo
+	_, err := ApplyOverlay(base, path)
+	if err == nil || !strings.Contains(err.Error(), path+": mode must be one of") || !strings.Contains(err.Error(), "(line 1, column 7)") {
+		t.Fatalf("expected mode error with overlay position, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/suryansh-23/secretty/internal/allowlist"
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+// PolicyModeOff is a policy-only mode that passes a command's output through
+// unredacted, like an allowlisted command.
+const PolicyModeOff types.Mode = "off"
+
+// Policy adjusts redaction while a matching command is the foreground
+// process of a session's terminal. Unset fields keep the session config.
+type Policy struct {
+	// Command is a glob matched like allowlist entries: against the
+	// basename of argv[0], or the full argv[0] when it contains a '/'.
+	Command string `yaml:"command"`
+	// Args is a regex matched against the arguments joined by spaces.
+	Args string `yaml:"args,omitempty"`
+
+	Mode   types.Mode   `yaml:"mode,omitempty"`
+	Action types.Action `yaml:"action,omitempty"`
+	// Rulesets turns named rulesets on or off.
+	Rulesets map[string]bool `yaml:"rulesets,omitempty"`
+	// Pause set to false keeps redaction on while the command runs, even
+	// if the session is paused.
+	Pause *bool `yaml:"pause,omitempty"`
+}
+
+// Pausable reports whether a session pause applies while the policy's
+// command runs.
+func (p Policy) Pausable() bool {
+	return p.Pause == nil || *p.Pause
+}
+
+// PolicyFor returns the index of the first policy matching argv, or -1.
+func (c Config) PolicyFor(argv []string) int {
+	if len(argv) == 0 {
+		return -1
+	}
+	args := strings.Join(argv[1:], " ")
+	for i, p := range c.Policies {
+		if ok, err := allowlist.Match([]string{p.Command}, argv[0], ""); err != nil || !ok {
+			continue
+		}
+		if p.Args != "" {
+			re, err := argsRegexp(p.Args)
+			if err != nil || !re.MatchString(args) {
+				continue
+			}
+		}
+		return i
+	}
+	return -1
+}
+
+// argsPatterns caches compiled policy args regexes by pattern. PolicyFor runs
+// whenever the foreground process changes and for every shell command.
+var argsPatterns sync.Map
+
+func argsRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := argsPatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	argsPatterns.Store(pattern, re)
+	return re, nil
+}
+
+// WithPolicy returns c with p's mode, action and rulesets applied. A policy
+// can only tighten a strict config: the mode stays strict, enabled rulesets
+// stay enabled and a warn action is ignored. PolicyModeOff leaves the mode
+// unchanged; callers bypass redaction for it unless the session is locked by
+// strict.lock_until_exit.
+func (c Config) WithPolicy(p Policy) Config {
+	cfg := c
+	cfg.Rulesets = maps.Clone(c.Rulesets)
+	if p.Mode != "" && p.Mode != PolicyModeOff {
+		cfg.Mode = p.Mode
+	}
+	for name, enabled := range p.Rulesets {
+		cfg.Rulesets.SetEnabled(name, enabled)
+	}
+	if p.Action != "" && (c.Mode != types.ModeStrict || p.Action != types.ActionWarn) {
+		cfg.Redaction.DefaultAction = p.Action
+		cfg.KnownSecrets.Action = p.Action
+		cfg.Rules = append([]Rule(nil), c.Rules...)
+		for i := range cfg.Rules {
+			cfg.Rules[i].Action = p.Action
+		}
+		cfg.TypedDetectors = append([]TypedDetector(nil), c.TypedDetectors...)
+		for i := range cfg.TypedDetectors {
+			cfg.TypedDetectors[i].Action = p.Action
+		}
+	}
+	tighten(c, &cfg)
+	return cfg
+}
+
+func (c Config) policyProblems() []string {
+	var errs []string
+	for i, p := range c.Policies {
+		field := fmt.Sprintf("policies[%d]", i)
+		if strings.TrimSpace(p.Command) == "" {
+			errs = append(errs, field+".command is required")
+		} else if _, err := path.Match(p.Command, "dummy"); err != nil {
+			errs = append(errs, fmt.Sprintf("%s.command has invalid pattern: %v", field, err))
+		}
+		if p.Args != "" {
+			if _, err := argsRegexp(p.Args); err != nil {
+				errs = append(errs, fmt.Sprintf("%s.args is invalid: %v", field, err))
+			}
+		}
+		if p.Mode != "" && p.Mode != PolicyModeOff && !validMode(p.Mode) {
+			errs = append(errs, fmt.Sprintf("%s.mode must be one of: %s, %s", field, strings.Join(validModes(), ", "), PolicyModeOff))
+		}
+		if p.Action != "" && !validAction(p.Action) {
+			errs = append(errs, field+".action must be mask, placeholder, or warn")
+		}
+		for _, name := range slices.Sorted(maps.Keys(p.Rulesets)) {
+			if _, ok := c.Rulesets[name]; !ok {
+				errs = append(errs, fmt.Sprintf("%s.rulesets %q is not defined under rulesets", field, name))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+const policyConfig = `version: 1
+mode: demo
+policies:
+  - command: vim
+    mode: off
+  - command: kubectl
+    args: '^get secrets?\b'
+    action: placeholder
+    rulesets: {structured: true}
+  - command: terraform
+    pause: false
+`
+
+func TestParsePoliciesAndMatch(t *testing.T) {
+	cfg, err := Parse([]byte(policyConfig))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	cases := []struct {
+		argv []string
+		want int
+	}{
+		{[]string{"/usr/bin/vim", "notes.txt"}, 0},
+		{[]string{"kubectl", "get", "secret", "db", "-o", "yaml"}, 1},
+		{[]string{"kubectl", "get", "pods"}, -1},
+		{[]string{"terraform", "apply"}, 2},
+		{[]string{"-zsh"}, -1},
+		{nil, -1},
+	}
+	for _, tc := range cases {
+		if got := cfg.PolicyFor(tc.argv); got != tc.want {
+			t.Fatalf("PolicyFor(%q) = %d, want %d", tc.argv, got, tc.want)
+		}
+	}
+	if cfg.Policies[0].Pausable() != true || cfg.Policies[2].Pausable() != false {
+		t.Fatalf("unexpected pause eligibility: %+v", cfg.Policies)
+	}
+}
+
+func TestWithPolicyAppliesActionAndRulesets(t *testing.T) {
+	cfg, err := Parse([]byte(policyConfig))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	got := cfg.WithPolicy(cfg.Policies[1])
+	if !got.Rulesets[RulesetStructured].Enabled || cfg.Rulesets[RulesetStructured].Enabled {
+		t.Fatalf("expected structured enabled for the policy only")
+	}
+	if got.Redaction.DefaultAction != types.ActionPlaceholder || got.Rules[0].Action != types.ActionPlaceholder || got.TypedDetectors[0].Action != types.ActionPlaceholder {
+		t.Fatalf("expected placeholder action everywhere")
+	}
+	if cfg.Rules[0].Action != types.ActionMask {
+		t.Fatalf("expected base rules untouched, got %s", cfg.Rules[0].Action)
+	}
+	if got := cfg.WithPolicy(Policy{Command: "less", Mode: types.ModeWarn}); got.Mode != types.ModeWarn {
+		t.Fatalf("expected warn mode, got %s", got.Mode)
+	}
+}
+
+func TestWithPolicyCannotLoosenStrict(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Rulesets.SetEnabled(RulesetEntropy, true)
+	got := cfg.WithPolicy(Policy{
+		Command:  "less",
+		Mode:     types.ModeDemo,
+		Action:   types.ActionWarn,
+		Rulesets: map[string]bool{RulesetEntropy: false, RulesetCloud: true},
+	})
+	if got.Mode != types.ModeStrict {
+		t.Fatalf("expected strict mode kept, got %s", got.Mode)
+	}
+	if got.Rules[0].Action != types.ActionMask {
+		t.Fatalf("expected warn action ignored, got %s", got.Rules[0].Action)
+	}
+	if !got.Rulesets[RulesetEntropy].Enabled || !got.Rulesets[RulesetCloud].Enabled {
+		t.Fatalf("expected rulesets only enabled, got %+v", got.Rulesets)
+	}
+}
+
+func TestValidationRejectsInvalidPolicies(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Policies = []Policy{
+		{Command: ""},
+		{Command: "[", Args: "(", Mode: "loud", Action: "shout", Rulesets: map[string]bool{"nope": true}},
+	}
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{
+		"policies[0].command is required",
+		"policies[1].command has invalid pattern",
+		"policies[1].args is invalid",
+		"policies[1].mode must be one of: demo, strict, warn, off",
+		"policies[1].action must be",
+		`policies[1].rulesets "nope" is not defined`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %v", want, err)
+		}
+	}
+}
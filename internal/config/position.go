@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// annotatePosition appends the YAML line and column of the field a problem
+// names. Problems about fields missing from the document point at the
+// nearest enclosing node; top-level misses are left unannotated.
+func annotatePosition(root *yaml.Node, problem string) string {
+	field, _, _ := strings.Cut(problem, " ")
+	node := locate(root, field)
+	if node == nil {
+		return problem
+	}
+	return fmt.Sprintf("%s (line %d, column %d)", problem, node.Line, node.Column)
+}
+
+// locate walks a field path such as rules[2].regex.pattern and returns the
+// deepest node it reaches below the document root.
+func locate(root *yaml.Node, field string) *yaml.Node {
+	node := root
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil
+		}
+		node = node.Content[0]
+	}
+	var found *yaml.Node
+	for _, step := range pathSteps(field) {
+		next := child(node, step)
+		if next == nil {
+			break
+		}
+		node = next
+		found = next
+	}
+	return found
+}
+
+func child(node *yaml.Node, step string) *yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == step {
+				return node.Content[i+1]
+			}
+		}
+	case yaml.SequenceNode:
+		idx, err := strconv.Atoi(step)
+		if err == nil && idx >= 0 && idx < len(node.Content) {
+			return node.Content[idx]
+		}
+	}
+	return nil
+}
+
+// pathSteps splits rules[2].regex into rules, 2, regex.
+func pathSteps(field string) []string {
+	var steps []string
+	for _, part := range strings.Split(field, ".") {
+		for part != "" {
+			open := strings.IndexByte(part, '[')
+			if open < 0 {
+				steps = append(steps, part)
+				break
+			}
+			if open > 0 {
+				steps = append(steps, part[:open])
+			}
+			end := strings.IndexByte(part[open:], ']')
+			if end < 0 {
+				break
+			}
+			steps = append(steps, part[open+1:open+end])
+			part = part[open+end+1:]
+		}
+	}
+	return steps
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// rulePack is an included file of rulesets, rules and typed detectors.
+type rulePack struct {
+	Path           string          `yaml:"-"`
+	Rulesets       yaml.Node       `yaml:"rulesets"`
+	Rules          []Rule          `yaml:"rules"`
+	TypedDetectors []TypedDetector `yaml:"typed_detectors"`
+
+	root yaml.Node
+}
+
+// loadRulePacks resolves include entries against baseDir and decodes each
+// pack. Entries may name a file, a directory (every .yaml or .yml file in
+// it) or a glob. A file or directory that does not exist is a problem; a
+// glob that matches nothing is not, so optional team directories can be
+// listed everywhere. A file reached twice is loaded once.
+func loadRulePacks(entries []string, baseDir string) ([]rulePack, []string, error) {
+	var (
+		packs    []rulePack
+		problems []string
+		seen     = map[string]bool{}
+	)
+	for i, entry := range entries {
+		paths, problem := resolveInclude(entry, baseDir)
+		if problem != "" {
+			problems = append(problems, fmt.Sprintf("include[%d] %s", i, problem))
+			continue
+		}
+		for _, path := range paths {
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+			pack, err := loadRulePack(path)
+			if err != nil {
+				return nil, nil, err
+			}
+			packs = append(packs, pack)
+		}
+	}
+	return packs, problems, nil
+}
+
+func resolveInclude(entry, baseDir string) ([]string, string) {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return nil, "must not be empty"
+	}
+	path := ExpandHome(entry)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	if strings.ContainsAny(path, "*?[") {
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Sprintf("has invalid pattern: %v", err)
+		}
+		return packFiles(matches), ""
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Sprintf("%s cannot be read: %v", entry, err)
+	}
+	if !info.IsDir() {
+		return []string{path}, ""
+	}
+	dirEntries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Sprintf("%s cannot be read: %v", entry, err)
+	}
+	var paths []string
+	for _, e := range dirEntries {
+		if !e.IsDir() {
+			paths = append(paths, filepath.Join(path, e.Name()))
+		}
+	}
+	return packFiles(paths), ""
+}
+
+// packFiles keeps .yaml and .yml paths in lexical order.
+func packFiles(paths []string) []string {
+	var out []string
+	for _, path := range paths {
+		if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" {
+			out = append(out, path)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func loadRulePack(path string) (rulePack, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rulePack{}, fmt.Errorf("read rule pack: %w", err)
+	}
+	pack := rulePack{Path: path}
+	if err := yaml.Unmarshal(data, &pack); err != nil {
+		return rulePack{}, fmt.Errorf("parse rule pack %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &pack.root); err != nil {
+		return rulePack{}, fmt.Errorf("parse rule pack %s: %w", path, err)
+	}
+	setRuleSource(pack.Rules, path)
+	setDetectorSource(pack.TypedDetectors, path)
+	return pack, nil
+}
+
+// applyRulesets merges the pack's rulesets into cfg, entry by entry.
+func (p rulePack) applyRulesets(cfg *Config) error {
+	if p.Rulesets.Kind == 0 {
+		return nil
+	}
+	if err := p.Rulesets.Decode(&cfg.Rulesets); err != nil {
+		return fmt.Errorf("parse rule pack %s: %w", p.Path, err)
+	}
+	return nil
+}
+
+// problems validates the pack's rules against the merged config and prefixes
+// each problem with the pack path and position.
+func (p rulePack) problems(cfg Config) []string {
+	problems := cfg.ruleProblems(p.Rules, p.TypedDetectors)
+	for i, problem := range problems {
+		problems[i] = p.Path + ": " + annotatePosition(&p.root, problem)
+	}
+	return problems
+}
+
+// mergeRulePacks validates packs against cfg and appends their rules and
+// typed detectors. Nothing is appended when any pack has problems.
+func mergeRulePacks(cfg *Config, packs []rulePack) []string {
+	var problems []string
+	for _, pack := range packs {
+		problems = append(problems, pack.problems(*cfg)...)
+	}
+	problems = append(problems, nameCollisions(*cfg, packs)...)
+	if len(problems) > 0 {
+		return problems
+	}
+	for _, pack := range packs {
+		cfg.Rules = append(cfg.Rules, pack.Rules...)
+		cfg.TypedDetectors = append(cfg.TypedDetectors, pack.TypedDetectors...)
+	}
+	return nil
+}
+
+// nameCollisions reports rules and typed detectors whose names are already
+// used by the config or an earlier pack. Both kinds share one namespace
+// because matches are reported by name.
+func nameCollisions(cfg Config, packs []rulePack) []string {
+	owner := map[string]string{}
+	for _, rule := range cfg.Rules {
+		owner[rule.Name] = sourceLabel(rule.Source)
+	}
+	for _, det := range cfg.TypedDetectors {
+		owner[det.Name] = sourceLabel(det.Source)
+	}
+	var problems []string
+	claim := func(pack rulePack, field, name string) {
+		if prev, ok := owner[name]; ok && name != "" {
+			problem := fmt.Sprintf("%s.name %q is already defined in %s", field, name, prev)
+			problems = append(problems, pack.Path+": "+annotatePosition(&pack.root, problem))
+			return
+		}
+		owner[name] = pack.Path
+	}
+	for _, pack := range packs {
+		for i, rule := range pack.Rules {
+			claim(pack, fmt.Sprintf("rules[%d]", i), rule.Name)
+		}
+		for i, det := range pack.TypedDetectors {
+			claim(pack, fmt.Sprintf("typed_detectors[%d]", i), det.Name)
+		}
+	}
+	return problems
+}
+
+func sourceLabel(source string) string {
+	if source == "" {
+		return "defaults"
+	}
+	return source
+}
+
+func setRuleSource(rules []Rule, path string) {
+	for i := range rules {
+		rules[i].Source = path
+	}
+}
+
+func setDetectorSource(dets []TypedDetector, path string) {
+	for i := range dets {
+		dets[i].Source = path
+	}
+}
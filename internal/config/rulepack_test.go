@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+const acmePack = `rulesets:
+  acme_internal:
+    enabled: true
+    description: "ACME service tokens"
+    action: placeholder
+    severity: high
+rules:
+  - name: acme_service_token
+    enabled: true
+    type: regex
+    secret_type: API_KEY
+    ruleset: acme_internal
+    regex:
+      pattern: "acme_[a-z0-9]{8}"
+`
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestLoadMergesIncludedRulePacks(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "rules.d", "10-acme.yaml"), acmePack)
+	writeFile(t, filepath.Join(dir, "rules.d", "README.md"), "not a pack")
+	writeFile(t, filepath.Join(dir, "repo.yaml"), `typed_detectors:
+  - name: acme_db_password
+    enabled: true
+    kind: CONNECTION_STRING
+    secret_type: PASSWORD
+    ruleset: acme_internal
+`)
+	cfgPath := filepath.Join(dir, "config.yaml")
+	writeFile(t, cfgPath, `version: 1
+include:
+  - rules.d
+  - repo.yaml
+  - optional.d/*.yaml
+`)
+
+	cfg, found, err := Load(cfgPath)
+	if err != nil || !found {
+		t.Fatalf("load: found=%t err=%v", found, err)
+	}
+	last := cfg.Rules[len(cfg.Rules)-1]
+	if last.Name != "acme_service_token" || last.Source != filepath.Join(dir, "rules.d", "10-acme.yaml") {
+		t.Fatalf("expected pack rule appended with its source, got %s from %q", last.Name, last.Source)
+	}
+	if cfg.Rules[0].Source != "" {
+		t.Fatalf("expected default rules to have no source, got %q", cfg.Rules[0].Source)
+	}
+	det := cfg.EffectiveDetector(cfg.TypedDetectors[len(cfg.TypedDetectors)-1])
+	if det.Name != "acme_db_password" || det.Action != types.ActionPlaceholder || det.Severity != types.SeverityHigh {
+		t.Fatalf("expected detector to inherit from a ruleset in another pack, got %+v", det)
+	}
+}
+
+func TestConfigRulesetsOverrideRulePacks(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "acme.yaml"), acmePack)
+	cfgPath := filepath.Join(dir, "config.yaml")
+	writeFile(t, cfgPath, `version: 1
+include: [acme.yaml]
+rulesets:
+  acme_internal:
+    enabled: false
+`)
+	cfg, _, err := Load(cfgPath)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	set := cfg.Rulesets["acme_internal"]
+	if set.Enabled || set.Action != types.ActionPlaceholder {
+		t.Fatalf("expected config to disable the pack ruleset and keep its action, got %+v", set)
+	}
+}
+
+func TestRulePackNameCollision(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.yaml"), acmePack)
+	writeFile(t, filepath.Join(dir, "b.yaml"), `rules:
+  - name: acme_service_token
+    enabled: true
+    type: regex
+    severity: high
+    regex:
+      pattern: "acme"
+  - name: stripe_key
+    enabled: true
+    type: regex
+    severity: high
+    regex:
+      pattern: "sk"
+`)
+	cfgPath := filepath.Join(dir, "config.yaml")
+	writeFile(t, cfgPath, "version: 1\ninclude: [a.yaml, b.yaml]\n")
+	_, _, err := Load(cfgPath)
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected invalid config, got %v", err)
+	}
+	msg := err.Error()
+	wantPack := filepath.Join(dir, "b.yaml") + `: rules[0].name "acme_service_token" is already defined in ` + filepath.Join(dir, "a.yaml") + " (line 2, column 11)"
+	if !strings.Contains(msg, wantPack) {
+		t.Fatalf("expected pack collision, got %q", msg)
+	}
+	if !strings.Contains(msg, `rules[1].name "stripe_key" is already defined in defaults`) {
+		t.Fatalf("expected default collision, got %q", msg)
+	}
+}
+
+func TestRulePackProblemsNameThePack(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "bad.yaml"), `rules:
+  - name: broken
+    enabled: true
+    type: regex
+    severity: high
+    ruleset: nowhere
+    regex:
+      pattern: "x"
+`)
+	cfgPath := filepath.Join(dir, "config.yaml")
+	writeFile(t, cfgPath, "version: 1\ninclude:\n  - bad.yaml\n  - missing.yaml\n")
+	_, _, err := Load(cfgPath)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, filepath.Join(dir, "bad.yaml")+`: rules[0].ruleset "nowhere" is not defined under rulesets (line 6, column 14)`) {
+		t.Fatalf("expected pack ruleset error, got %q", msg)
+	}
+	if !strings.Contains(msg, "include[1] missing.yaml cannot be read") || !strings.Contains(msg, "(line 4, column 5)") {
+		t.Fatalf("expected missing include error, got %q", msg)
+	}
+}
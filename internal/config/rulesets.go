@@ -0,0 +1,249 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+// Built-in ruleset names. Detectors that read ruleset options look them up
+// by these names.
+const (
+	RulesetWeb3        = "web3"
+	RulesetAPIKeys     = "api_keys"
+	RulesetAuthTokens  = "auth_tokens"
+	RulesetCloud       = "cloud"
+	RulesetPasswords   = "passwords"
+	RulesetConnStrings = "connection_strings"
+	RulesetEntropy     = "entropy"
+	RulesetStructured  = "structured"
+)
+
+var rulesetNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Rulesets maps a ruleset name to its settings. Rules and typed detectors
+// join a ruleset through their ruleset field and only run while it is
+// enabled.
+type Rulesets map[string]Ruleset
+
+// Ruleset groups rules under one toggle. A non-empty Action or Severity is
+// inherited by member rules that leave theirs unset.
+type Ruleset struct {
+	Enabled     bool           `yaml:"enabled"`
+	Description string         `yaml:"description,omitempty"`
+	Action      types.Action   `yaml:"action,omitempty"`
+	Severity    types.Severity `yaml:"severity,omitempty"`
+
+	// AllowBare64Hex lets the web3 ruleset match 64-hex keys without a 0x
+	// prefix or context keyword.
+	AllowBare64Hex bool `yaml:"allow_bare_64hex,omitempty"`
+
+	// Hex and Base64 are the entropy ruleset's per-charset thresholds.
+	Hex    EntropyThreshold `yaml:"hex,omitempty"`
+	Base64 EntropyThreshold `yaml:"base64,omitempty"`
+
+	// Keys are the structured ruleset's sensitive key globs, matched
+	// against snake_case key names.
+	Keys []string `yaml:"keys,omitempty"`
+}
+
+// EntropyThreshold sets the minimum token length and Shannon entropy, in
+// bits per character, for one charset.
+type EntropyThreshold struct {
+	MinLength  int     `yaml:"min_length"`
+	MinEntropy float64 `yaml:"min_entropy"`
+}
+
+// DefaultRulesets returns the built-in rulesets. Only web3 is enabled.
+func DefaultRulesets() Rulesets {
+	return Rulesets{
+		RulesetWeb3: {
+			Enabled:     true,
+			Description: "EVM keys, seed phrases and base58 wallet keys",
+		},
+		RulesetAPIKeys: {
+			Description: "Vendor API keys (GitHub, Slack, Stripe, ...)",
+		},
+		RulesetAuthTokens: {
+			Description: "JWTs and bearer tokens",
+		},
+		RulesetCloud: {
+			Description: "Cloud credentials (AWS, GCP, Azure, DigitalOcean, Heroku, Cloudflare, Vercel)",
+		},
+		RulesetPasswords: {
+			Description: "password= style assignments",
+		},
+		RulesetConnStrings: {
+			Description: "Passwords in DB and broker connection strings",
+		},
+		RulesetEntropy: {
+			Description: "High-entropy strings near secret keywords",
+			Hex: EntropyThreshold{
+				MinLength:  32,
+				MinEntropy: 3.0,
+			},
+			Base64: EntropyThreshold{
+				MinLength:  24,
+				MinEntropy: 4.0,
+			},
+		},
+		RulesetStructured: {
+			Description: "Values of sensitive keys in JSON, YAML, dotenv, TOML and HCL output",
+			Keys:        DefaultStructuredKeys(),
+		},
+	}
+}
+
+// DefaultStructuredKeys returns the key globs the structured ruleset masks
+// by default.
+func DefaultStructuredKeys() []string {
+	return []string{
+		"password", "*_password", "passwd", "*_passwd", "passphrase", "*_passphrase",
+		"secret", "*_secret", "secret_key", "*_secret_key", "*secret_access_key",
+		"token", "*_token", "auth",
+		"api_key", "*_api_key", "apikey", "*_apikey",
+		"private_key", "*_private_key",
+	}
+}
+
+// UnmarshalYAML decodes each ruleset on top of the existing entry of the same
+// name, so a partial override such as `entropy: {enabled: true}` keeps the
+// default thresholds and description.
+func (r *Rulesets) UnmarshalYAML(value *yaml.Node) error {
+	var raw map[string]yaml.Node
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+	if *r == nil {
+		*r = Rulesets{}
+	}
+	for name, node := range raw {
+		set := (*r)[name]
+		if err := node.Decode(&set); err != nil {
+			return err
+		}
+		(*r)[name] = set
+	}
+	return nil
+}
+
+// Names returns the ruleset names in sorted order.
+func (r Rulesets) Names() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetEnabled turns a defined ruleset on or off. Undefined names are ignored.
+func (r Rulesets) SetEnabled(name string, enabled bool) {
+	set, ok := r[name]
+	if !ok {
+		return
+	}
+	set.Enabled = enabled
+	r[name] = set
+}
+
+// RulesetEnabled reports whether a ruleset is active. The empty name is
+// always active; an undefined ruleset is not.
+func RulesetEnabled(name string, sets Rulesets) bool {
+	if name == "" {
+		return true
+	}
+	return sets[name].Enabled
+}
+
+// EffectiveRule returns rule with an unset action or severity inherited from
+// its ruleset. An action still unset falls back to redaction.default_action
+// when the match is rendered.
+func (c Config) EffectiveRule(rule Rule) Rule {
+	rule.Action, rule.Severity = c.inherit(rule.Ruleset, rule.Action, rule.Severity)
+	return rule
+}
+
+// EffectiveDetector is EffectiveRule for typed detectors.
+func (c Config) EffectiveDetector(det TypedDetector) TypedDetector {
+	det.Action, det.Severity = c.inherit(det.Ruleset, det.Action, det.Severity)
+	return det
+}
+
+func (c Config) inherit(ruleset string, action types.Action, severity types.Severity) (types.Action, types.Severity) {
+	set, ok := c.Rulesets[ruleset]
+	if !ok {
+		return action, severity
+	}
+	if action == "" {
+		action = set.Action
+	}
+	if severity == "" {
+		severity = set.Severity
+	}
+	return action, severity
+}
+
+func (r Rulesets) problems() []string {
+	var errs []string
+	for _, name := range r.Names() {
+		set := r[name]
+		field := "rulesets." + name
+		if !rulesetNamePattern.MatchString(name) {
+			errs = append(errs, field+" name must be lowercase letters, digits and underscores")
+		}
+		if set.Action != "" && !validAction(set.Action) {
+			errs = append(errs, field+".action must be mask, placeholder, or warn")
+		}
+		if set.Severity != "" && !validSeverity(set.Severity) {
+			errs = append(errs, field+".severity must be low|med|high")
+		}
+	}
+	if entropy, ok := r[RulesetEntropy]; ok {
+		errs = append(errs, entropyThresholdProblems("rulesets.entropy.hex", entropy.Hex, 4)...)
+		errs = append(errs, entropyThresholdProblems("rulesets.entropy.base64", entropy.Base64, 6)...)
+	}
+	if structured, ok := r[RulesetStructured]; ok {
+		errs = append(errs, keyGlobProblems("rulesets.structured.keys", structured.Keys)...)
+	}
+	return errs
+}
+
+func keyGlobProblems(field string, keys []string) []string {
+	var errs []string
+	for i, key := range keys {
+		if strings.TrimSpace(key) == "" {
+			errs = append(errs, fmt.Sprintf("%s[%d] must not be empty", field, i))
+			continue
+		}
+		if _, err := path.Match(key, ""); err != nil {
+			errs = append(errs, fmt.Sprintf("%s[%d] %q is not a valid glob", field, i, key))
+		}
+	}
+	return errs
+}
+
+// memberProblems checks a rule's ruleset reference and its action and
+// severity after inheritance.
+func (c Config) memberProblems(field, ruleset string, action types.Action, severity types.Severity) []string {
+	var errs []string
+	if ruleset != "" {
+		if _, ok := c.Rulesets[ruleset]; !ok {
+			errs = append(errs, fmt.Sprintf("%s.ruleset %q is not defined under rulesets", field, ruleset))
+		}
+	}
+	action, severity = c.inherit(ruleset, action, severity)
+	if action != "" && !validAction(action) {
+		errs = append(errs, field+".action must be mask, placeholder, or warn")
+	}
+	if !validSeverity(severity) {
+		errs = append(errs, field+".severity must be low|med|high")
+	}
+	return errs
+}
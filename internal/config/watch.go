@@ -0,0 +1,48 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// DefaultWatchInterval is how often Watch polls the config file.
+const DefaultWatchInterval = 2 * time.Second
+
+// Watch polls path and calls onChange after its size or modification time
+// changes. It returns when ctx is done.
+func Watch(ctx context.Context, path string, interval time.Duration, onChange func()) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	last := fileStamp(path)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := fileStamp(path)
+			if current == last {
+				continue
+			}
+			last = current
+			onChange()
+		}
+	}
+}
+
+type stamp struct {
+	size    int64
+	modTime time.Time
+	exists  bool
+}
+
+func fileStamp(path string) stamp {
+	info, err := os.Stat(path)
+	if err != nil {
+		return stamp{}
+	}
+	return stamp{size: info.Size(), modTime: info.ModTime(), exists: true}
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchReportsChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("version: 1\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changed := make(chan struct{}, 1)
+	go Watch(ctx, path, 10*time.Millisecond, func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+
+	time.Sleep(30 * time.Millisecond)
+	if err := os.WriteFile(path, []byte("version: 1\nmode: demo\n"), 0o600); err != nil {
+		t.Fatalf("rewrite: %v", err)
+	}
+	select {
+	case <-changed:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("expected change notification")
+	}
+}
@@ -0,0 +1,282 @@
+// Package corpus verifies detectors against annotated fixture files.
+package corpus
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/suryansh-23/secretty/internal/config"
+	"github.com/suryansh-23/secretty/internal/detect"
+	"github.com/suryansh-23/secretty/internal/redact"
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+// Fixture is one annotated corpus file.
+type Fixture struct {
+	Path        string        `yaml:"-"`
+	Description string        `yaml:"description"`
+	Input       string        `yaml:"input"`
+	Expect      []Expectation `yaml:"expect"`
+	Reject      []Expectation `yaml:"reject"`
+}
+
+// Expectation names a detection on a 1-based input line. In rejections Rule
+// is optional and an empty Rule rejects every detection on the line.
+type Expectation struct {
+	Line int              `yaml:"line"`
+	Rule string           `yaml:"rule"`
+	Type types.SecretType `yaml:"type,omitempty"`
+}
+
+// FailureKind classifies a corpus mismatch.
+type FailureKind string
+
+const (
+	FalsePositive FailureKind = "false positive"
+	FalseNegative FailureKind = "false negative"
+	NotRedacted   FailureKind = "not redacted"
+)
+
+// Failure describes one mismatch between a fixture and the detectors.
+type Failure struct {
+	Fixture string
+	Line    int
+	Rule    string
+	Kind    FailureKind
+	Detail  string
+}
+
+func (f Failure) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s: %s", f.Fixture, f.Line, f.Rule, f.Kind, f.Detail)
+}
+
+// RuleStats counts outcomes for one rule across the corpus.
+type RuleStats struct {
+	Rule           string
+	Expected       int
+	Detected       int
+	FalsePositives int
+	FalseNegatives int
+}
+
+// Report summarizes a corpus run.
+type Report struct {
+	Fixtures int
+	Failures []Failure
+	Rules    []RuleStats
+}
+
+// OK reports whether every fixture matched its annotations.
+func (r Report) OK() bool {
+	return len(r.Failures) == 0
+}
+
+// Load reads every .yaml or .yml fixture in dir, sorted by name.
+func Load(dir string) ([]Fixture, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read corpus: %w", err)
+	}
+	var fixtures []Fixture
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		fixture, err := loadFixture(path)
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, fixture)
+	}
+	if len(fixtures) == 0 {
+		return nil, fmt.Errorf("no fixtures in %s", dir)
+	}
+	return fixtures, nil
+}
+
+func loadFixture(path string) (Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Fixture{}, fmt.Errorf("read fixture: %w", err)
+	}
+	var fixture Fixture
+	if err := yaml.Unmarshal(data, &fixture); err != nil {
+		return Fixture{}, fmt.Errorf("%s: %w", path, err)
+	}
+	fixture.Path = path
+	lines := strings.Count(fixture.Input, "\n") + 1
+	for i, exp := range fixture.Expect {
+		if exp.Rule == "" {
+			return Fixture{}, fmt.Errorf("%s: expect[%d].rule is required", path, i)
+		}
+		if exp.Line < 1 || exp.Line > lines {
+			return Fixture{}, fmt.Errorf("%s: expect[%d].line %d is outside the input", path, i, exp.Line)
+		}
+	}
+	for i, exp := range fixture.Reject {
+		if exp.Line < 1 || exp.Line > lines {
+			return Fixture{}, fmt.Errorf("%s: reject[%d].line %d is outside the input", path, i, exp.Line)
+		}
+	}
+	return fixture, nil
+}
+
+// Verify runs fixtures through the detector engine and redactor built from
+// cfg. Every detection must be expected, every expectation detected, and
+// every expected secret absent from the redacted output unless it resolves
+// to a warn action.
+func Verify(fixtures []Fixture, cfg config.Config) (Report, error) {
+	engine, err := detect.NewEngine(cfg)
+	if err != nil {
+		return Report{}, err
+	}
+	redactor := redact.NewRedactor(cfg)
+	stats := map[string]*RuleStats{}
+	stat := func(rule string) *RuleStats {
+		if stats[rule] == nil {
+			stats[rule] = &RuleStats{Rule: rule}
+		}
+		return stats[rule]
+	}
+
+	report := Report{Fixtures: len(fixtures)}
+	for _, fixture := range fixtures {
+		text := []byte(fixture.Input)
+		matches := engine.Find(text)
+		redacted, err := redactor.Apply(text, matches)
+		if err != nil {
+			return Report{}, fmt.Errorf("%s: %w", fixture.Path, err)
+		}
+		used := make([]bool, len(matches))
+		fail := func(line int, rule string, kind FailureKind, detail string) {
+			report.Failures = append(report.Failures, Failure{Fixture: fixture.Path, Line: line, Rule: rule, Kind: kind, Detail: detail})
+		}
+
+		for _, exp := range fixture.Expect {
+			stat(exp.Rule).Expected++
+			idx := findMatch(text, matches, used, exp.Line, exp.Rule)
+			if idx < 0 {
+				stat(exp.Rule).FalseNegatives++
+				fail(exp.Line, exp.Rule, FalseNegative, missingDetail(cfg, exp.Rule))
+				continue
+			}
+			used[idx] = true
+			m := matches[idx]
+			if exp.Type != "" && m.SecretType != exp.Type {
+				stat(exp.Rule).FalseNegatives++
+				fail(exp.Line, exp.Rule, FalseNegative, fmt.Sprintf("detected as %s, want %s", m.SecretType, exp.Type))
+				continue
+			}
+			stat(exp.Rule).Detected++
+			if !warnAction(cfg, m) && bytes.Contains(redacted, text[m.Start:m.End]) {
+				fail(exp.Line, exp.Rule, NotRedacted, "original bytes remain in redacted output")
+			}
+		}
+
+		for i, m := range matches {
+			if used[i] {
+				continue
+			}
+			line := lineOf(text, m.Start)
+			stat(m.RuleName).FalsePositives++
+			detail := fmt.Sprintf("unexpected %s detection", m.SecretType)
+			if rejected(fixture.Reject, line, m.RuleName) {
+				detail = fmt.Sprintf("%s detected on a line marked as non-detection", m.SecretType)
+			}
+			fail(line, m.RuleName, FalsePositive, detail)
+		}
+	}
+
+	for _, s := range stats {
+		report.Rules = append(report.Rules, *s)
+	}
+	sort.Slice(report.Rules, func(i, j int) bool {
+		return report.Rules[i].Rule < report.Rules[j].Rule
+	})
+	return report, nil
+}
+
+// Check loads the corpus in dir and reports each failure through t.
+func Check(t testing.TB, dir string, cfg config.Config) Report {
+	t.Helper()
+	fixtures, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load corpus: %v", err)
+	}
+	report, err := Verify(fixtures, cfg)
+	if err != nil {
+		t.Fatalf("verify corpus: %v", err)
+	}
+	for _, failure := range report.Failures {
+		t.Errorf("%s", failure)
+	}
+	return report
+}
+
+func findMatch(text []byte, matches []redact.Match, used []bool, line int, rule string) int {
+	for i, m := range matches {
+		if !used[i] && m.RuleName == rule && lineOf(text, m.Start) == line {
+			return i
+		}
+	}
+	return -1
+}
+
+// missingDetail explains a false negative, naming configuration that keeps
+// the rule from running.
+func missingDetail(cfg config.Config, name string) string {
+	check := func(enabled bool, ruleset string) string {
+		switch {
+		case !enabled:
+			return "expected detection not found (rule is disabled)"
+		case ruleset != "" && !config.RulesetEnabled(ruleset, cfg.Rulesets):
+			return fmt.Sprintf("expected detection not found (ruleset %s is disabled)", ruleset)
+		default:
+			return "expected detection not found"
+		}
+	}
+	for _, rule := range cfg.Rules {
+		if rule.Name == name {
+			return check(rule.Enabled, rule.Ruleset)
+		}
+	}
+	for _, det := range cfg.TypedDetectors {
+		if det.Name == name {
+			return check(det.Enabled, det.Ruleset)
+		}
+	}
+	return "expected detection not found (no such rule)"
+}
+
+func rejected(rejects []Expectation, line int, rule string) bool {
+	for _, r := range rejects {
+		if r.Line == line && (r.Rule == "" || r.Rule == rule) {
+			return true
+		}
+	}
+	return false
+}
+
+func warnAction(cfg config.Config, m redact.Match) bool {
+	if cfg.Mode == types.ModeWarn {
+		return true
+	}
+	action := m.Action
+	if action == "" {
+		action = cfg.Redaction.DefaultAction
+	}
+	return action == types.ActionWarn && !cfg.NoRevealEnforced()
+}
+
+func lineOf(text []byte, offset int) int {
+	return bytes.Count(text[:offset], []byte("\n")) + 1
+}
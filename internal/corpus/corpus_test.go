@@ -0,0 +1,83 @@
+package corpus
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/config"
+)
+
+func allRulesetsConfig() config.Config {
+	cfg := config.DefaultConfig()
+	for _, name := range cfg.Rulesets.Names() {
+		cfg.Rulesets.SetEnabled(name, true)
+	}
+	return cfg
+}
+
+func TestDefaultRulesCorpus(t *testing.T) {
+	report := Check(t, filepath.Join("testdata", "default"), allRulesetsConfig())
+	covered := map[string]bool{}
+	for _, s := range report.Rules {
+		covered[s.Rule] = s.Detected > 0
+	}
+	cfg := config.DefaultConfig()
+	for _, rule := range cfg.Rules {
+		if rule.Enabled && !covered[rule.Name] {
+			t.Errorf("default rule %s has no corpus detection", rule.Name)
+		}
+	}
+	for _, det := range cfg.TypedDetectors {
+		if det.Enabled && !covered[det.Name] {
+			t.Errorf("default detector %s has no corpus detection", det.Name)
+		}
+	}
+}
+
+func TestVerifyReportsMismatches(t *testing.T) {
+	dir := t.TempDir()
+	fixture := `input: |
+  PRIVATE_KEY=0x4c0883a69102937d6231471b5dbb6204fe5129617082791b2b2f2c0f4b1c2d3e
+  nothing here
+expect:
+  - {line: 2, rule: evm_private_key}
+reject:
+  - {line: 1}
+`
+	if err := os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte(fixture), 0o600); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	fixtures, err := Load(dir)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	report, err := Verify(fixtures, config.DefaultConfig())
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if report.OK() || len(report.Failures) != 2 {
+		t.Fatalf("expected 2 failures, got %+v", report.Failures)
+	}
+	if report.Failures[0].Kind != FalseNegative || report.Failures[1].Kind != FalsePositive {
+		t.Fatalf("unexpected failure kinds: %+v", report.Failures)
+	}
+	if !strings.Contains(report.Failures[1].Detail, "marked as non-detection") {
+		t.Fatalf("detail = %q", report.Failures[1].Detail)
+	}
+	if len(report.Rules) != 1 || report.Rules[0].FalseNegatives != 1 || report.Rules[0].FalsePositives != 1 {
+		t.Fatalf("unexpected stats: %+v", report.Rules)
+	}
+}
+
+func TestLoadRejectsOutOfRangeLine(t *testing.T) {
+	dir := t.TempDir()
+	fixture := "input: one line\nexpect:\n  - {line: 3, rule: github_pat}\n"
+	if err := os.WriteFile(filepath.Join(dir, "f.yaml"), []byte(fixture), 0o600); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), "outside the input") {
+		t.Fatalf("expected line range error, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package detect
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/big"
+	"regexp"
+
+	"github.com/suryansh-23/secretty/internal/config"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	// Extended private keys are 82 decoded bytes, always 111 characters.
+	extendedKeyPattern = regexp.MustCompile(`\b[xtyz]prv[1-9A-HJ-NP-Za-km-z]{107}\b`)
+	// WIF keys are 51 characters uncompressed (5 or 9) or 52 compressed
+	// (K, L or c).
+	wifPattern = regexp.MustCompile(`\b(?:[59][1-9A-HJ-NP-Za-km-z]{50}|[KLc][1-9A-HJ-NP-Za-km-z]{51})\b`)
+
+	// extendedPrivateVersions are the BIP32/49/84 version bytes for xprv,
+	// tprv, yprv and zprv. Public versions (xpub and friends) are excluded.
+	extendedPrivateVersions = [][]byte{
+		{0x04, 0x88, 0xad, 0xe4},
+		{0x04, 0x35, 0x83, 0x94},
+		{0x04, 0x9d, 0x78, 0x78},
+		{0x04, 0xb2, 0x43, 0x0c},
+	}
+)
+
+// extendedKeyKind detects base58check BIP32 extended private keys.
+type extendedKeyKind struct{}
+
+func newExtendedKeyKind(config.Config) TypedKind {
+	return extendedKeyKind{}
+}
+
+func (extendedKeyKind) Scan(text []byte) [][2]int {
+	return regexSpans(extendedKeyPattern, text)
+}
+
+// Validate checks the checksum, a private version prefix, and the 0x00 pad
+// byte that precedes the 32-byte private key.
+func (extendedKeyKind) Validate(candidate []byte) bool {
+	payload, ok := base58CheckDecode(candidate)
+	if !ok || len(payload) != 78 || payload[45] != 0x00 {
+		return false
+	}
+	for _, version := range extendedPrivateVersions {
+		if bytes.Equal(payload[:4], version) {
+			return true
+		}
+	}
+	return false
+}
+
+func (extendedKeyKind) Score([]byte) int {
+	return 0
+}
+
+// wifKind detects base58check wallet import format private keys for mainnet
+// (0x80) and testnet (0xef).
+type wifKind struct{}
+
+func newWifKind(config.Config) TypedKind {
+	return wifKind{}
+}
+
+func (wifKind) Scan(text []byte) [][2]int {
+	return regexSpans(wifPattern, text)
+}
+
+func (wifKind) Validate(candidate []byte) bool {
+	payload, ok := base58CheckDecode(candidate)
+	if !ok || len(payload) < 33 {
+		return false
+	}
+	if payload[0] != 0x80 && payload[0] != 0xef {
+		return false
+	}
+	switch len(payload) {
+	case 33:
+		return true
+	case 34:
+		return payload[33] == 0x01
+	default:
+		return false
+	}
+}
+
+func (wifKind) Score([]byte) int {
+	return 0
+}
+
+// base58CheckDecode decodes token and verifies its trailing 4-byte double
+// SHA-256 checksum, returning the payload without the checksum.
+func base58CheckDecode(token []byte) ([]byte, bool) {
+	decoded, ok := base58Decode(token)
+	if !ok || len(decoded) < 5 {
+		return nil, false
+	}
+	payload, checksum := decoded[:len(decoded)-4], decoded[len(decoded)-4:]
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], checksum) {
+		return nil, false
+	}
+	return payload, true
+}
+
+func base58Decode(token []byte) ([]byte, bool) {
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, b := range token {
+		digit := bytes.IndexByte([]byte(base58Alphabet), b)
+		if digit < 0 {
+			return nil, false
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+	zeros := 0
+	for zeros < len(token) && token[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), n.Bytes()...), true
+}
@@ -0,0 +1,59 @@
+package detect
+
+import (
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/config"
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+const (
+	testXprv    = "xprv9s21ZrQH143K2TYjqRvwwqeLyyuypjCUdavqEEv5kWcwsNqM4kBrwgugs5jo6TwPKwW2iMqCqoJFg2jdiA9oK69cdXjD9BqwTjy7t5NEK5Y"
+	testTprv    = "tprv8ZgxMBicQKsPdGnGVznT7VGLJ7LC4FEUy8qx6fLYEV7ReyaS47XcTSH8nFuT6qKhhP2oiTSy19t48tHNqNVk89RDAAwWoYZzNqiYKrLs9UT"
+	testXpub    = "xpub661MyMwAqRbcEwdCwTTxJyb5Y1kUEBvKzorS2dKhJr9vkBAVcHW7VVEAiLmZY6z3SXJUtT7YKiHiC7UyS3snNMRXipBoUp2nNus8dQTDuAh"
+	testWIF     = "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ"
+	testWIFComp = "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617"
+	testWIFTest = "cMcfH8sRgBgDMfpBNG6H3haaxLkaYXgqMRef8Nev6tWyBSNr6c3n"
+)
+
+func TestBase58KeysDetected(t *testing.T) {
+	engine := newTestEngine(t, config.DefaultConfig())
+	cases := []struct {
+		key  string
+		rule string
+		typ  types.SecretType
+	}{
+		{testXprv, "extended_private_key", types.SecretExtendedKey},
+		{testTprv, "extended_private_key", types.SecretExtendedKey},
+		{testWIF, "wif_private_key", types.SecretWIFKey},
+		{testWIFComp, "wif_private_key", types.SecretWIFKey},
+		{testWIFTest, "wif_private_key", types.SecretWIFKey},
+	}
+	for _, tc := range cases {
+		input := []byte("key: " + tc.key + "\n")
+		matches := engine.Find(input)
+		if len(matches) != 1 {
+			t.Fatalf("%s: matches = %+v", tc.key, matches)
+		}
+		m := matches[0]
+		if m.RuleName != tc.rule || m.SecretType != tc.typ || string(input[m.Start:m.End]) != tc.key {
+			t.Fatalf("%s: unexpected match %+v", tc.key, m)
+		}
+	}
+}
+
+func TestBase58KeysRejectPublicAndCorrupt(t *testing.T) {
+	engine := newTestEngine(t, config.DefaultConfig())
+	corrupt := []byte(testWIFComp)
+	corrupt[20] = 'z'
+	for _, input := range []string{
+		testXpub,
+		testXprv[:110] + "Z",
+		string(corrupt),
+		"Kx2bPz8A8uQ4K8LmT9XhM4RmQn8VnK3f7Zs2pYwE1cR6dJ3tHgUb",
+	} {
+		if matches := engine.Find([]byte(input)); len(matches) != 0 {
+			t.Fatalf("%s: expected no matches, got %+v", input, matches)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package detect
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"strings"
+
+	"github.com/suryansh-23/secretty/internal/config"
+)
+
+//go:embed bip39_english.txt
+var bip39English string
+
+// bip39Index maps each BIP39 English word to its 11-bit index.
+var bip39Index = func() map[string]int {
+	words := strings.Fields(bip39English)
+	if len(words) != 2048 {
+		panic("detect: bip39 wordlist must have 2048 words")
+	}
+	index := make(map[string]int, len(words))
+	for i, w := range words {
+		index[w] = i
+	}
+	return index
+}()
+
+// bip39Lengths lists valid mnemonic word counts, longest first so a run
+// prefers the longest phrase that checksums.
+var bip39Lengths = []int{24, 21, 18, 15, 12}
+
+// bip39Kind detects BIP39 English mnemonics. Words may be separated by any
+// whitespace, so a phrase that wraps across lines is one candidate.
+type bip39Kind struct{}
+
+func newBip39Kind(config.Config) TypedKind {
+	return bip39Kind{}
+}
+
+// Scan splits text into runs of consecutive wordlist words and returns the
+// longest checksum-valid phrases in each run. A run with no valid phrase is
+// still returned when its length is a valid word count, so Explain can
+// report the checksum failure.
+func (bip39Kind) Scan(text []byte) [][2]int {
+	var spans [][2]int
+	for _, run := range bip39Runs(text) {
+		found := false
+		for i := 0; i+bip39Lengths[len(bip39Lengths)-1] <= len(run); {
+			n := bip39PhraseAt(text, run[i:])
+			if n == 0 {
+				i++
+				continue
+			}
+			spans = append(spans, [2]int{run[i][0], run[i+n-1][1]})
+			found = true
+			i += n
+		}
+		if !found && bip39ValidCount(len(run)) {
+			spans = append(spans, [2]int{run[0][0], run[len(run)-1][1]})
+		}
+	}
+	return spans
+}
+
+func (bip39Kind) Validate(candidate []byte) bool {
+	words := strings.Fields(string(candidate))
+	if !bip39ValidCount(len(words)) {
+		return false
+	}
+	indices := make([]int, len(words))
+	for i, w := range words {
+		idx, ok := bip39Index[w]
+		if !ok {
+			return false
+		}
+		indices[i] = idx
+	}
+	return bip39Checksum(indices)
+}
+
+func (bip39Kind) Score([]byte) int {
+	return 0
+}
+
+// bip39Runs returns word spans grouped into runs of wordlist words separated
+// only by whitespace.
+func bip39Runs(text []byte) [][][2]int {
+	var runs [][][2]int
+	var run [][2]int
+	flush := func() {
+		if len(run) >= bip39Lengths[len(bip39Lengths)-1] {
+			runs = append(runs, run)
+		}
+		run = nil
+	}
+	i := 0
+	for i < len(text) {
+		b := text[i]
+		switch {
+		case isBip39Space(b):
+			i++
+		case b >= 'a' && b <= 'z':
+			start := i
+			for i < len(text) && text[i] >= 'a' && text[i] <= 'z' {
+				i++
+			}
+			if i < len(text) && !isBip39Space(text[i]) {
+				// Words glued to punctuation or digits are not mnemonic words.
+				flush()
+				for i < len(text) && !isBip39Space(text[i]) {
+					i++
+				}
+				continue
+			}
+			if _, ok := bip39Index[string(text[start:i])]; !ok {
+				flush()
+				continue
+			}
+			run = append(run, [2]int{start, i})
+		default:
+			flush()
+			for i < len(text) && !isBip39Space(text[i]) {
+				i++
+			}
+		}
+	}
+	flush()
+	return runs
+}
+
+// bip39PhraseAt returns the longest valid word count whose phrase starting at
+// words[0] passes the checksum, or 0.
+func bip39PhraseAt(text []byte, words [][2]int) int {
+	for _, n := range bip39Lengths {
+		if n > len(words) {
+			continue
+		}
+		indices := make([]int, n)
+		for i, w := range words[:n] {
+			indices[i] = bip39Index[string(text[w[0]:w[1]])]
+		}
+		if bip39Checksum(indices) {
+			return n
+		}
+	}
+	return 0
+}
+
+// bip39Checksum packs 11-bit word indices and checks that the trailing
+// checksum bits equal the leading bits of SHA-256 over the entropy.
+func bip39Checksum(indices []int) bool {
+	totalBits := len(indices) * 11
+	checksumBits := totalBits / 33
+	entropyBits := totalBits - checksumBits
+	bits := make([]byte, (totalBits+7)/8)
+	for i, idx := range indices {
+		for j := 0; j < 11; j++ {
+			if idx&(1<<(10-j)) != 0 {
+				pos := i*11 + j
+				bits[pos/8] |= 1 << (7 - pos%8)
+			}
+		}
+	}
+	entropy := bits[:entropyBits/8]
+	sum := sha256.Sum256(entropy)
+	for j := 0; j < checksumBits; j++ {
+		pos := entropyBits + j
+		got := bits[pos/8]&(1<<(7-pos%8)) != 0
+		want := sum[0]&(1<<(7-j)) != 0
+		if got != want {
+			return false
+		}
+	}
+	return true
+}
+
+func bip39ValidCount(n int) bool {
+	for _, valid := range bip39Lengths {
+		if n == valid {
+			return true
+		}
+	}
+	return false
+}
+
+func isBip39Space(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
@@ -0,0 +1,66 @@
+package detect
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/config"
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+const (
+	testMnemonic12 = "legal winner thank year wave sausage worth useful legal winner thank yellow"
+	testMnemonic24 = "good clinic oil speak note cup random punch hunt logic frame tragic elder robot target august kidney swim butter woman way dolphin anxiety ladder"
+)
+
+func TestBip39MatchesWholePhrase(t *testing.T) {
+	engine := newTestEngine(t, config.DefaultConfig())
+	for _, phrase := range []string{testMnemonic12, testMnemonic24} {
+		input := []byte("mnemonic: " + phrase + "\n")
+		matches := engine.Find(input)
+		if len(matches) != 1 {
+			t.Fatalf("matches = %+v", matches)
+		}
+		m := matches[0]
+		if m.RuleName != "bip39_mnemonic" || m.SecretType != types.SecretSeedPhrase {
+			t.Fatalf("unexpected match %+v", m)
+		}
+		if got := string(input[m.Start:m.End]); got != phrase {
+			t.Fatalf("match text = %q", got)
+		}
+	}
+}
+
+func TestBip39MatchesAcrossLineWrap(t *testing.T) {
+	engine := newTestEngine(t, config.DefaultConfig())
+	words := strings.Fields(testMnemonic24)
+	wrapped := strings.Join(words[:9], " ") + "\r\n" + strings.Join(words[9:17], " ") + "\n  " + strings.Join(words[17:], " ")
+	input := []byte("seed phrase:\n" + wrapped + "\n$ ")
+	matches := engine.Find(input)
+	if len(matches) != 1 {
+		t.Fatalf("matches = %+v", matches)
+	}
+	if got := string(input[matches[0].Start:matches[0].End]); got != wrapped {
+		t.Fatalf("match text = %q", got)
+	}
+}
+
+func TestBip39RejectsBadChecksum(t *testing.T) {
+	engine := newTestEngine(t, config.DefaultConfig())
+	phrase := strings.Replace(testMnemonic12, "yellow", "year", 1)
+	if matches := engine.Find([]byte(phrase)); len(matches) != 0 {
+		t.Fatalf("expected checksum failure, got %+v", matches)
+	}
+	ex := engine.Explain([]byte(phrase))
+	if len(ex.Dropped) != 1 || ex.Dropped[0].RuleName != "bip39_mnemonic" {
+		t.Fatalf("expected dropped bip39 candidate, got %+v", ex.Dropped)
+	}
+}
+
+func TestBip39IgnoresProse(t *testing.T) {
+	engine := newTestEngine(t, config.DefaultConfig())
+	input := []byte("The wallet is ready. Please write down your recovery phrase and keep it safe.")
+	if matches := engine.Find(input); len(matches) != 0 {
+		t.Fatalf("expected no matches, got %+v", matches)
+	}
+}
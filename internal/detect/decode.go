@@ -0,0 +1,144 @@
+package detect
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Encodings recognised by decode-and-scan.
+const (
+	encodingBase64  = "base64"
+	encodingPercent = "percent"
+	encodingJSON    = "json"
+)
+
+var (
+	base64Span  = regexp.MustCompile(`[A-Za-z0-9+/_\-]{16,}={0,2}`)
+	percentSpan = regexp.MustCompile(`[A-Za-z0-9._~+\-/=:@!$*,;%]*%[0-9A-Fa-f]{2}[A-Za-z0-9._~+\-/=:@!$*,;%]*`)
+	jsonString  = regexp.MustCompile(`"(?:[^"\\\n]|\\.)*\\(?:[^"\\\n]|\\.)*"`)
+)
+
+// encodedSpan is a span of text and the value it decodes to.
+type encodedSpan struct {
+	start    int
+	end      int
+	encoding string
+	decoded  []byte
+}
+
+// findDecodedMatches decodes base64, percent-encoded and JSON-escaped spans
+// and runs detection over each decoded value in its line context. A secret
+// found inside yields a candidate covering the encoded span. Spans longer
+// than maxSpan are skipped, and depth bounds nested decoding.
+func (e *Engine) findDecodedMatches(text []byte, depth int) []candidate {
+	var out []candidate
+	for _, span := range e.encodedSpans(text) {
+		lineStart := max(span.start-contextWindow, bytes.LastIndexByte(text[:span.start], '\n')+1)
+		lineEnd := min(span.end+contextWindow, len(text))
+		if i := bytes.IndexByte(text[span.end:lineEnd], '\n'); i >= 0 {
+			lineEnd = span.end + i
+		}
+		prefix := text[lineStart:span.start]
+		inner := make([]byte, 0, len(prefix)+len(span.decoded)+lineEnd-span.end)
+		inner = append(inner, prefix...)
+		inner = append(inner, span.decoded...)
+		inner = append(inner, text[span.end:lineEnd]...)
+		valueStart, valueEnd := len(prefix), len(prefix)+len(span.decoded)
+
+		var best *candidate
+		for _, cand := range resolveOverlaps(e.collect(inner, nil, depth-1), nil) {
+			if cand.match.End <= valueStart || cand.match.Start >= valueEnd {
+				continue
+			}
+			if best == nil {
+				c := cand
+				best = &c
+				continue
+			}
+			if better, _ := betterCandidate(cand, *best); better {
+				c := cand
+				best = &c
+			}
+		}
+		if best == nil {
+			continue
+		}
+		cand := *best
+		cand.match.Start = span.start
+		cand.match.End = span.end
+		cand.length = span.end - span.start
+		cand.encoding = joinEncoding(span.encoding, cand.encoding)
+		out = append(out, cand)
+	}
+	return out
+}
+
+func (e *Engine) encodedSpans(text []byte) []encodedSpan {
+	var spans []encodedSpan
+	add := func(start, end int, encoding string, decoded []byte, ok bool) {
+		if !ok || end-start > e.decode.MaxSpanBytes || !printable(decoded) || bytes.Equal(decoded, text[start:end]) {
+			return
+		}
+		spans = append(spans, encodedSpan{start: start, end: end, encoding: encoding, decoded: decoded})
+	}
+	for _, idx := range base64Span.FindAllIndex(text, -1) {
+		decoded, ok := decodeBase64(text[idx[0]:idx[1]])
+		add(idx[0], idx[1], encodingBase64, decoded, ok)
+	}
+	for _, idx := range percentSpan.FindAllIndex(text, -1) {
+		decoded, err := url.QueryUnescape(string(text[idx[0]:idx[1]]))
+		add(idx[0], idx[1], encodingPercent, []byte(decoded), err == nil)
+	}
+	for _, idx := range jsonString.FindAllIndex(text, -1) {
+		var decoded string
+		err := json.Unmarshal(text[idx[0]:idx[1]], &decoded)
+		// The span excludes the quotes so they stay visible.
+		add(idx[0]+1, idx[1]-1, encodingJSON, []byte(decoded), err == nil)
+	}
+	return spans
+}
+
+// decodeBase64 accepts standard and URL-safe alphabets, padded or not.
+func decodeBase64(token []byte) ([]byte, bool) {
+	trimmed := bytes.TrimRight(token, "=")
+	if len(trimmed)%4 == 1 {
+		return nil, false
+	}
+	enc := base64.RawStdEncoding
+	if bytes.ContainsAny(trimmed, "-_") {
+		if bytes.ContainsAny(trimmed, "+/") {
+			return nil, false
+		}
+		enc = base64.RawURLEncoding
+	}
+	decoded, err := enc.DecodeString(string(trimmed))
+	if err != nil {
+		return nil, false
+	}
+	return decoded, true
+}
+
+// printable reports whether decoded is UTF-8 text without control bytes
+// other than tabs and line breaks.
+func printable(decoded []byte) bool {
+	if len(decoded) == 0 || !utf8.Valid(decoded) {
+		return false
+	}
+	for _, b := range decoded {
+		if (b < 0x20 && b != '\t' && b != '\n' && b != '\r') || b == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+func joinEncoding(outer, inner string) string {
+	if inner == "" {
+		return outer
+	}
+	return outer + "+" + inner
+}
@@ -0,0 +1,87 @@
+package detect
+
+import (
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/config"
+)
+
+func decodeConfig() config.Config {
+	cfg := config.DefaultConfig()
+	cfg.Rulesets.SetEnabled(config.RulesetAPIKeys, true)
+	cfg.Decode.Enabled = true
+	return cfg
+}
+
+func TestDecodeFindsEncodedSecrets(t *testing.T) {
+	engine := newTestEngine(t, decodeConfig())
+	cases := []struct {
+		input    string
+		encoded  string
+		rule     string
+		encoding string
+	}{
+		{
+			input:    "data:\n  token: Z2hwXzAxMjM0NTY3ODlBQkNERUZHSGlqa2xtbm9wcXJzdHV2d3h5eg==\n",
+			encoded:  "Z2hwXzAxMjM0NTY3ODlBQkNERUZHSGlqa2xtbm9wcXJzdHV2d3h5eg==",
+			rule:     "github_pat",
+			encoding: "base64",
+		},
+		{
+			input:    "> GET /?h=x-api-key%3A%20abcdEFGH1234567890XYZ HTTP/1.1\n",
+			encoded:  "h=x-api-key%3A%20abcdEFGH1234567890XYZ",
+			rule:     "api_key_label",
+			encoding: "percent",
+		},
+		{
+			input:    `{"token":"ghp_\u0030123456789ABCDEFGHijklmnopqrstuvwxyz"}`,
+			encoded:  `ghp_\u0030123456789ABCDEFGHijklmnopqrstuvwxyz`,
+			rule:     "github_pat",
+			encoding: "json",
+		},
+	}
+	for _, tc := range cases {
+		input := []byte(tc.input)
+		ex := engine.Explain(input)
+		if len(ex.Kept) != 1 {
+			t.Fatalf("%q: kept = %+v", tc.input, ex.Kept)
+		}
+		f := ex.Kept[0]
+		if got := string(input[f.Start:f.End]); got != tc.encoded {
+			t.Fatalf("%q: span = %q, want %q", tc.input, got, tc.encoded)
+		}
+		if f.RuleName != tc.rule || f.Encoding != tc.encoding {
+			t.Fatalf("%q: rule=%s encoding=%s", tc.input, f.RuleName, f.Encoding)
+		}
+	}
+}
+
+func TestDecodeDepthAndSizeLimits(t *testing.T) {
+	// base64 of "x-api-key%3A%20abcdEFGH1234567890XYZ" needs two layers.
+	input := []byte("v=eC1hcGkta2V5JTNBJTIwYWJjZEVGR0gxMjM0NTY3ODkwWFla\n")
+	cfg := decodeConfig()
+	ex := newTestEngine(t, cfg).Explain(input)
+	if len(ex.Kept) != 1 || ex.Kept[0].Encoding != "base64+percent" {
+		t.Fatalf("expected nested decode, got %+v", ex.Kept)
+	}
+
+	cfg.Decode.MaxDepth = 1
+	if matches := newTestEngine(t, cfg).Find(input); len(matches) != 0 {
+		t.Fatalf("expected depth limit to stop nested decode, got %+v", matches)
+	}
+
+	cfg = decodeConfig()
+	cfg.Decode.MaxSpanBytes = 32
+	if matches := newTestEngine(t, cfg).Find(input); len(matches) != 0 {
+		t.Fatalf("expected size limit to skip span, got %+v", matches)
+	}
+}
+
+func TestDecodeDisabledByDefault(t *testing.T) {
+	cfg := decodeConfig()
+	cfg.Decode.Enabled = false
+	input := []byte("token: Z2hwXzAxMjM0NTY3ODlBQkNERUZHSGlqa2xtbm9wcXJzdHV2d3h5eg==")
+	if matches := newTestEngine(t, cfg).Find(input); len(matches) != 0 {
+		t.Fatalf("expected no matches, got %+v", matches)
+	}
+}
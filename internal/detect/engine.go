@@ -1,6 +1,7 @@
 package detect
 
 import (
+	"fmt"
 	"regexp"
 	"sort"
 	"strings"
@@ -17,13 +18,20 @@ type sourceKind int
 const (
 	sourceRegex sourceKind = iota
 	sourceTyped
+	sourceKnown
 )
 
 type candidate struct {
-	match    redact.Match
-	severity int
-	source   sourceKind
-	length   int
+	match      redact.Match
+	severity   int
+	level      types.Severity
+	source     sourceKind
+	length     int
+	keywords   bool
+	contextHit bool
+	// encoding names the decode layers, outermost first, when the match was
+	// found inside encoded text.
+	encoding string
 }
 
 type compiledRule struct {
@@ -36,34 +44,32 @@ type compiledRule struct {
 
 type typedDetector struct {
 	detector config.TypedDetector
+	kind     TypedKind
 	severity int
 	keywords []string
 }
 
-// Engine detects secrets using regex rules and typed validators.
+// Engine detects secrets using regex rules, typed validators and, when
+// enabled, fingerprints of known secret values.
 type Engine struct {
-	regexRules []compiledRule
-	typed      []typedDetector
-
-	evmWithPrefix *regexp.Regexp
-	evmBare       *regexp.Regexp
-
-	allowBare64Hex bool
+	regexRules  []compiledRule
+	typed       []typedDetector
+	known       *KnownSecrets
+	knownAction types.Action
+	decode      config.Decode
 }
 
-// NewEngine builds a detector engine from config.
-func NewEngine(cfg config.Config) *Engine {
-	allowBare64Hex := cfg.Rulesets.Web3.AllowBare64Hex
-	if !cfg.Rulesets.Web3.Enabled {
-		allowBare64Hex = false
-	}
-	engine := &Engine{
-		evmWithPrefix:  regexp.MustCompile(`0x[0-9a-fA-F]{64}`),
-		evmBare:        regexp.MustCompile(`\b[0-9a-fA-F]{64}\b`),
-		allowBare64Hex: allowBare64Hex,
+// NewEngine builds a detector engine from config. It fails if an enabled
+// rule's pattern does not compile or its group is out of range, or if known
+// secrets are enabled and a source cannot be read.
+func NewEngine(cfg config.Config) (*Engine, error) {
+	engine := &Engine{}
+	if cfg.Decode.Enabled {
+		engine.decode = cfg.Decode
 	}
 
 	for _, rule := range cfg.Rules {
+		rule = cfg.EffectiveRule(rule)
 		if !rule.Enabled {
 			continue
 		}
@@ -76,9 +82,16 @@ func NewEngine(cfg config.Config) *Engine {
 		if rule.Regex == nil || rule.Regex.Pattern == "" {
 			continue
 		}
+		re, err := regexp.Compile(rule.Regex.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: compile pattern: %w", rule.Name, err)
+		}
+		if rule.Regex.Group < 0 || rule.Regex.Group > re.NumSubexp() {
+			return nil, fmt.Errorf("rule %s: regex.group %d out of range (pattern has %d capture groups)", rule.Name, rule.Regex.Group, re.NumSubexp())
+		}
 		engine.regexRules = append(engine.regexRules, compiledRule{
 			rule:     rule,
-			re:       regexp.MustCompile(rule.Regex.Pattern),
+			re:       re,
 			group:    rule.Regex.Group,
 			severity: severityRank(rule.Severity),
 			keywords: lowerKeywords(rule.ContextKeywords),
@@ -86,32 +99,53 @@ func NewEngine(cfg config.Config) *Engine {
 	}
 
 	for _, det := range cfg.TypedDetectors {
+		det = cfg.EffectiveDetector(det)
 		if !det.Enabled {
 			continue
 		}
 		if !config.RulesetEnabled(det.Ruleset, cfg.Rulesets) {
 			continue
 		}
+		kind, err := buildKind(cfg, det)
+		if err != nil {
+			return nil, err
+		}
 		engine.typed = append(engine.typed, typedDetector{
 			detector: det,
+			kind:     kind,
 			severity: severityRank(det.Severity),
 			keywords: lowerKeywords(det.ContextKeywords),
 		})
 	}
 
+	if cfg.KnownSecrets.Enabled {
+		known, err := LoadKnownSecrets(cfg.KnownSecrets)
+		if err != nil {
+			return nil, err
+		}
+		engine.known = known
+		engine.knownAction = cfg.KnownSecrets.Action
+	}
+
+	return engine, nil
+}
+
+// NewDefaultEngine builds an engine from the built-in default config, whose
+// patterns are known to compile.
+func NewDefaultEngine() *Engine {
+	engine, err := NewEngine(config.DefaultConfig())
+	if err != nil {
+		panic(fmt.Sprintf("default config: %v", err))
+	}
 	return engine
 }
 
 // Find returns redaction matches within text.
 func (e *Engine) Find(text []byte) []redact.Match {
-	var candidates []candidate
-	candidates = append(candidates, e.findRegexMatches(text)...)
-	candidates = append(candidates, e.findTypedMatches(text)...)
-
-	if len(candidates) == 0 {
+	resolved := e.resolve(text, nil)
+	if len(resolved) == 0 {
 		return nil
 	}
-	resolved := resolveOverlaps(candidates)
 	matches := make([]redact.Match, 0, len(resolved))
 	for _, cand := range resolved {
 		matches = append(matches, cand.match)
@@ -119,7 +153,30 @@ func (e *Engine) Find(text []byte) []redact.Match {
 	return matches
 }
 
-func (e *Engine) findRegexMatches(text []byte) []candidate {
+// resolve collects candidates and drops overlaps. When ex is non-nil every
+// rejected candidate is recorded with its reason.
+func (e *Engine) resolve(text []byte, ex *Explanation) []candidate {
+	candidates := e.collect(text, ex, e.decode.MaxDepth)
+	if len(candidates) == 0 {
+		return nil
+	}
+	return resolveOverlaps(candidates, ex)
+}
+
+// collect gathers candidates from every source. depth is the number of
+// decode layers still allowed below this text.
+func (e *Engine) collect(text []byte, ex *Explanation, depth int) []candidate {
+	var candidates []candidate
+	candidates = append(candidates, e.findRegexMatches(text, ex)...)
+	candidates = append(candidates, e.findTypedMatches(text, ex)...)
+	candidates = append(candidates, e.findKnownMatches(text)...)
+	if depth > 0 {
+		candidates = append(candidates, e.findDecodedMatches(text, depth)...)
+	}
+	return candidates
+}
+
+func (e *Engine) findRegexMatches(text []byte, ex *Explanation) []candidate {
 	if len(e.regexRules) == 0 {
 		return nil
 	}
@@ -132,14 +189,11 @@ func (e *Engine) findRegexMatches(text []byte) []candidate {
 			if start < 0 || end <= start {
 				continue
 			}
-			if len(rule.keywords) > 0 && !hasContextKeyword(text, start, end, rule.keywords) {
-				continue
-			}
 			secretType := rule.rule.SecretType
 			if secretType == "" {
 				secretType = types.SecretUnknown
 			}
-			out = append(out, candidate{
+			cand := candidate{
 				match: redact.Match{
 					Start:      start,
 					End:        end,
@@ -148,59 +202,55 @@ func (e *Engine) findRegexMatches(text []byte) []candidate {
 					RuleName:   rule.rule.Name,
 				},
 				severity: rule.severity,
+				level:    rule.rule.Severity,
 				source:   sourceRegex,
 				length:   end - start,
-			})
+				keywords: len(rule.keywords) > 0,
+			}
+			if cand.keywords {
+				cand.contextHit = hasContextKeyword(text, start, end, rule.keywords)
+				if !cand.contextHit {
+					ex.drop(cand, fmt.Sprintf("no context keyword within %d bytes", contextWindow))
+					continue
+				}
+			}
+			out = append(out, cand)
 		}
 	}
 	return out
 }
 
-func (e *Engine) findTypedMatches(text []byte) []candidate {
+func (e *Engine) findTypedMatches(text []byte, ex *Explanation) []candidate {
 	if len(e.typed) == 0 {
 		return nil
 	}
-	str := string(text)
 	var out []candidate
 	for _, det := range e.typed {
-		if det.detector.Kind != "EVM_PRIVATE_KEY" {
-			continue
-		}
-		for _, idx := range e.evmWithPrefix.FindAllStringIndex(str, -1) {
-			out = append(out, e.buildTypedCandidate(text, idx[0], idx[1], det)...)
-		}
-		if e.allowBare64Hex {
-			for _, idx := range e.evmBare.FindAllStringIndex(str, -1) {
-				out = append(out, e.buildTypedCandidate(text, idx[0], idx[1], det)...)
-			}
+		for _, span := range det.kind.Scan(text) {
+			out = append(out, buildTypedCandidate(text, span[0], span[1], det, ex)...)
 		}
 	}
 	return out
 }
 
-func (e *Engine) buildTypedCandidate(text []byte, start, end int, det typedDetector) []candidate {
+func buildTypedCandidate(text []byte, start, end int, det typedDetector, ex *Explanation) []candidate {
 	if start < 0 || end <= start || end > len(text) {
 		return nil
 	}
 	matchBytes := text[start:end]
-	score := 0
-	if validateEvmPrivateKey(matchBytes, e.allowBare64Hex) {
-		score += 2
-	}
-	if hasContextKeyword(text, start, end, det.keywords) {
-		score++
+	contextHit := hasContextKeyword(text, start, end, det.keywords)
+	score := det.kind.Score(matchBytes)
+	if det.kind.Validate(matchBytes) {
+		score += validScore
 	}
-	if has0xPrefix(matchBytes) {
-		score++
-	}
-	if score < 2 {
-		return nil
+	if contextHit {
+		score += contextScore
 	}
 	secretType := det.detector.SecretType
 	if secretType == "" {
 		secretType = types.SecretUnknown
 	}
-	return []candidate{{
+	cand := candidate{
 		match: redact.Match{
 			Start:      start,
 			End:        end,
@@ -208,37 +258,18 @@ func (e *Engine) buildTypedCandidate(text []byte, start, end int, det typedDetec
 			SecretType: secretType,
 			RuleName:   det.detector.Name,
 		},
-		severity: det.severity,
-		source:   sourceTyped,
-		length:   end - start,
-	}}
-}
-
-func validateEvmPrivateKey(token []byte, allowBare bool) bool {
-	if len(token) >= 2 && token[0] == '0' && (token[1] == 'x' || token[1] == 'X') {
-		return isHex(token[2:]) && len(token[2:]) == 64
-	}
-	if !allowBare {
-		return false
-	}
-	return len(token) == 64 && isHex(token)
-}
-
-func isHex(token []byte) bool {
-	for _, b := range token {
-		switch {
-		case b >= '0' && b <= '9':
-		case b >= 'a' && b <= 'f':
-		case b >= 'A' && b <= 'F':
-		default:
-			return false
-		}
+		severity:   det.severity,
+		level:      det.detector.Severity,
+		source:     sourceTyped,
+		length:     end - start,
+		keywords:   len(det.keywords) > 0,
+		contextHit: contextHit,
+	}
+	if score < minTypedScore {
+		ex.drop(cand, fmt.Sprintf("validator score %d below %d", score, minTypedScore))
+		return nil
 	}
-	return true
-}
-
-func has0xPrefix(token []byte) bool {
-	return len(token) >= 2 && token[0] == '0' && (token[1] == 'x' || token[1] == 'X')
+	return []candidate{cand}
 }
 
 func hasContextKeyword(text []byte, start, end int, keywords []string) bool {
@@ -287,8 +318,11 @@ func captureBounds(submatches []int, group int) (int, int) {
 	return submatches[idx], submatches[idx+1]
 }
 
-func resolveOverlaps(candidates []candidate) []candidate {
-	sort.Slice(candidates, func(i, j int) bool {
+// resolveOverlaps keeps the best of each group of overlapping candidates.
+// The sort is stable so candidates that tie on every factor resolve in the
+// order they were collected, which follows the rule order in the config.
+func resolveOverlaps(candidates []candidate, ex *Explanation) []candidate {
+	sort.SliceStable(candidates, func(i, j int) bool {
 		if candidates[i].match.Start == candidates[j].match.Start {
 			return candidates[i].match.End < candidates[j].match.End
 		}
@@ -305,24 +339,41 @@ func resolveOverlaps(candidates []candidate) []candidate {
 			out = append(out, cand)
 			continue
 		}
-		if betterCandidate(cand, last) {
+		if better, reason := betterCandidate(cand, last); better {
 			out[len(out)-1] = cand
+			ex.drop(last, overlapReason(cand, reason))
+		} else {
+			ex.drop(cand, overlapReason(last, reason))
 		}
 	}
 	return out
 }
 
-func betterCandidate(a, b candidate) bool {
+// betterCandidate reports whether a beats b and names the deciding factor.
+func betterCandidate(a, b candidate) (bool, string) {
 	if a.severity != b.severity {
-		return a.severity > b.severity
+		return a.severity > b.severity, "higher severity"
 	}
 	if a.source != b.source {
-		return a.source == sourceTyped
+		if a.source == sourceKnown || b.source == sourceKnown {
+			return a.source == sourceKnown, "known secret preferred"
+		}
+		return a.source == sourceTyped, "typed detector preferred over regex"
 	}
 	if a.length != b.length {
-		return a.length > b.length
+		return a.length > b.length, "longer match"
+	}
+	if a.keywords != b.keywords {
+		return !a.keywords, "specific rule preferred over label rule"
 	}
-	return a.match.Start < b.match.Start
+	if a.match.Start != b.match.Start {
+		return a.match.Start < b.match.Start, "earlier start"
+	}
+	return false, "earlier rule"
+}
+
+func overlapReason(winner candidate, reason string) string {
+	return fmt.Sprintf("overlaps %s (%s)", winner.match.RuleName, reason)
 }
 
 func severityRank(severity types.Severity) int {
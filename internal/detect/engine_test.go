@@ -1,16 +1,19 @@
 package detect
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/suryansh-23/secretty/internal/config"
+	"github.com/suryansh-23/secretty/internal/redact"
 	"github.com/suryansh-23/secretty/internal/types"
 )
 
 func TestRegexRuleMatch(t *testing.T) {
 	cfg := config.DefaultConfig()
-	engine := NewEngine(cfg)
+	engine := newTestEngine(t, cfg)
 	key := "0x" + strings.Repeat("a", 64)
 	input := []byte("PRIVATE_KEY=" + key)
 	matches := engine.Find(input)
@@ -28,8 +31,8 @@ func TestRegexRuleMatch(t *testing.T) {
 
 func TestTypedDetectorRequiresAllowBare(t *testing.T) {
 	cfg := config.DefaultConfig()
-	cfg.Rulesets.Web3.AllowBare64Hex = false
-	engine := NewEngine(cfg)
+	cfg.Rulesets[config.RulesetWeb3] = config.Ruleset{Enabled: true, AllowBare64Hex: false}
+	engine := newTestEngine(t, cfg)
 	key := strings.Repeat("a", 64)
 	matches := engine.Find([]byte(key))
 	if len(matches) != 0 {
@@ -39,7 +42,7 @@ func TestTypedDetectorRequiresAllowBare(t *testing.T) {
 
 func TestTypedDetectorAccepts0x(t *testing.T) {
 	cfg := config.DefaultConfig()
-	engine := NewEngine(cfg)
+	engine := newTestEngine(t, cfg)
 	key := "0x" + strings.Repeat("b", 64)
 	matches := engine.Find([]byte("key=" + key))
 	if len(matches) == 0 {
@@ -49,8 +52,8 @@ func TestTypedDetectorAccepts0x(t *testing.T) {
 
 func TestOverlapResolutionPrefersTyped(t *testing.T) {
 	cfg := config.DefaultConfig()
-	cfg.Rulesets.Web3.AllowBare64Hex = true
-	engine := NewEngine(cfg)
+	cfg.Rulesets[config.RulesetWeb3] = config.Ruleset{Enabled: true, AllowBare64Hex: true}
+	engine := newTestEngine(t, cfg)
 	key := strings.Repeat("c", 64)
 	input := []byte("PRIVATE_KEY=" + key)
 	matches := engine.Find(input)
@@ -62,10 +65,26 @@ func TestOverlapResolutionPrefersTyped(t *testing.T) {
 	}
 }
 
+func TestOverlapResolutionPrefersSpecificRuleOnTie(t *testing.T) {
+	label := candidate{match: redact.Match{Start: 11, End: 40, RuleName: "password_label"}, severity: 3, length: 29, keywords: true}
+	specific := candidate{match: redact.Match{Start: 11, End: 40, RuleName: "pypi_token"}, severity: 3, length: 29}
+	for _, order := range [][]candidate{{label, specific}, {specific, label}} {
+		kept := resolveOverlaps(order, nil)
+		if len(kept) != 1 || kept[0].match.RuleName != "pypi_token" {
+			t.Fatalf("expected pypi_token to win, got %+v", kept)
+		}
+	}
+	first := candidate{match: redact.Match{Start: 0, End: 8, RuleName: "first"}, length: 8}
+	second := candidate{match: redact.Match{Start: 0, End: 8, RuleName: "second"}, length: 8}
+	if kept := resolveOverlaps([]candidate{first, second}, nil); kept[0].match.RuleName != "first" {
+		t.Fatalf("expected the earlier rule to win a full tie, got %q", kept[0].match.RuleName)
+	}
+}
+
 func TestRulesetDisableSkipsDetection(t *testing.T) {
 	cfg := config.DefaultConfig()
-	cfg.Rulesets.Web3.Enabled = false
-	engine := NewEngine(cfg)
+	cfg.Rulesets.SetEnabled(config.RulesetWeb3, false)
+	engine := newTestEngine(t, cfg)
 	key := "0x" + strings.Repeat("a", 64)
 	matches := engine.Find([]byte("PRIVATE_KEY=" + key))
 	if len(matches) != 0 {
@@ -75,7 +94,7 @@ func TestRulesetDisableSkipsDetection(t *testing.T) {
 
 func TestRegexContextKeywordGatesMatch(t *testing.T) {
 	cfg := config.DefaultConfig()
-	cfg.Rulesets.AuthTokens.Enabled = true
+	cfg.Rulesets.SetEnabled(config.RulesetAuthTokens, true)
 	cfg.Rules = []config.Rule{{
 		Name:       "token_generic",
 		Enabled:    true,
@@ -91,7 +110,7 @@ func TestRegexContextKeywordGatesMatch(t *testing.T) {
 		ContextKeywords: []string{"token"},
 	}}
 
-	engine := NewEngine(cfg)
+	engine := newTestEngine(t, cfg)
 	if matches := engine.Find([]byte("abc12345")); len(matches) != 0 {
 		t.Fatalf("expected no matches without context")
 	}
@@ -99,3 +118,99 @@ func TestRegexContextKeywordGatesMatch(t *testing.T) {
 		t.Fatalf("expected match with context keyword")
 	}
 }
+
+func TestCustomRulesetActionIsInherited(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Rulesets["acme_internal"] = config.Ruleset{Enabled: true, Action: types.ActionWarn, Severity: types.SeverityMed}
+	cfg.Rules = []config.Rule{{
+		Name:       "acme_service_token",
+		Enabled:    true,
+		Type:       config.RuleTypeRegex,
+		SecretType: types.SecretAPIKey,
+		Ruleset:    "acme_internal",
+		Regex:      &config.RegexRule{Pattern: "acme_[a-z0-9]{8}"},
+	}}
+	engine := newTestEngine(t, cfg)
+	matches := engine.Find([]byte("using acme_1a2b3c4d"))
+	if len(matches) != 1 || matches[0].Action != types.ActionWarn {
+		t.Fatalf("expected one warn match, got %+v", matches)
+	}
+
+	cfg.Rulesets.SetEnabled("acme_internal", false)
+	engine = newTestEngine(t, cfg)
+	if matches := engine.Find([]byte("using acme_1a2b3c4d")); len(matches) != 0 {
+		t.Fatalf("expected no matches when acme_internal is disabled, got %+v", matches)
+	}
+}
+
+func newTestEngine(t *testing.T, cfg config.Config) *Engine {
+	t.Helper()
+	engine, err := NewEngine(cfg)
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	return engine
+}
+
+func TestNewEngineRejectsInvalidPattern(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Rulesets.SetEnabled(config.RulesetAPIKeys, true)
+	cfg.Rules = append(cfg.Rules, config.Rule{
+		Name:     "broken",
+		Enabled:  true,
+		Type:     config.RuleTypeRegex,
+		Action:   types.ActionMask,
+		Severity: types.SeverityHigh,
+		Ruleset:  "api_keys",
+		Regex:    &config.RegexRule{Pattern: "token=([a-z", Group: 1},
+	})
+	if _, err := NewEngine(cfg); err == nil || !strings.Contains(err.Error(), "rule broken") {
+		t.Fatalf("expected compile error for rule broken, got %v", err)
+	}
+}
+
+func TestUntrustedOverlayRuleCannotWarnOverMaskedSecret(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, config.ProjectFileName)
+	overlay := `rules:
+  - name: project_env_line
+    enabled: true
+    type: regex
+    action: warn
+    severity: high
+    secret_type: GENERIC_SECRET
+    regex:
+      pattern: "PRIVATE_KEY=\\S+"
+`
+	if err := os.WriteFile(path, []byte(overlay), 0o600); err != nil {
+		t.Fatalf("write overlay: %v", err)
+	}
+	// env_private_key masks the value; the overlay rule's longer span wins
+	// the overlap.
+	input := []byte("PRIVATE_KEY=correct-horse-battery\n")
+
+	base := config.DefaultConfig()
+	base.Mode = types.ModeDemo
+	cfg, err := config.ApplyOverlay(base, path)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	matches := newTestEngine(t, cfg).Find(input)
+	if len(matches) == 0 {
+		t.Fatal("expected the private key to be detected")
+	}
+	for _, m := range matches {
+		if m.Action == types.ActionWarn {
+			t.Fatalf("untrusted overlay rule %s warns over a masked secret", m.RuleName)
+		}
+	}
+
+	base.TrustedOverlays = []string{dir}
+	if cfg, err = config.ApplyOverlay(base, path); err != nil {
+		t.Fatalf("apply trusted: %v", err)
+	}
+	matches = newTestEngine(t, cfg).Find(input)
+	if len(matches) != 1 || matches[0].RuleName != "project_env_line" || matches[0].Action != types.ActionWarn {
+		t.Fatalf("expected the trusted overlay rule to apply, got %+v", matches)
+	}
+}
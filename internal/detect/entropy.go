@@ -0,0 +1,199 @@
+package detect
+
+import (
+	"bytes"
+	"math"
+	"regexp"
+
+	"github.com/suryansh-23/secretty/internal/config"
+)
+
+// entropyStrongMargin is how far above its charset threshold a token must be
+// to be kept without a nearby context keyword.
+const entropyStrongMargin = 0.5
+
+var (
+	entropyToken = regexp.MustCompile(`[A-Za-z0-9+/_\-]+={0,2}`)
+	armorBegin   = regexp.MustCompile(`-----BEGIN ([A-Z0-9 ]+)-----`)
+	uuidPattern  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+	// digestPrefixes mark content digests such as sha256:<hex> image IDs.
+	digestPrefixes = [][]byte{[]byte("sha256:"), []byte("sha512:"), []byte("sha1:"), []byte("md5:")}
+	// hashSumLengths are the hex lengths printed by md5sum through sha512sum.
+	hashSumLengths = map[int]bool{32: true, 40: true, 56: true, 64: true, 96: true, 128: true}
+)
+
+// entropyKind detects prefix-less secrets by Shannon entropy and character
+// mix, with thresholds from rulesets.entropy. Tokens that only look high
+// entropy without a context keyword must clear the threshold by
+// entropyStrongMargin.
+type entropyKind struct {
+	hex    config.EntropyThreshold
+	base64 config.EntropyThreshold
+}
+
+func newEntropyKind(cfg config.Config) TypedKind {
+	entropy := cfg.Rulesets[config.RulesetEntropy]
+	return entropyKind{hex: entropy.Hex, base64: entropy.Base64}
+}
+
+// Scan returns tokens long enough for their charset, skipping armored
+// blocks, UUIDs, public wallet identifiers, git SHAs after "commit", content
+// digests and hashsum output lines.
+func (k entropyKind) Scan(text []byte) [][2]int {
+	var spans [][2]int
+	armored := armoredRanges(text)
+	for _, idx := range entropyToken.FindAllIndex(text, -1) {
+		start, end := idx[0], idx[1]
+		if inRanges(armored, start) {
+			continue
+		}
+		token := text[start:end]
+		if len(token) < k.threshold(token).MinLength {
+			continue
+		}
+		if uuidPattern.Match(token) || isPublicIdentifier(token) || isHashOutput(text, start, end) {
+			continue
+		}
+		spans = append(spans, [2]int{start, end})
+	}
+	return spans
+}
+
+func (k entropyKind) Validate(candidate []byte) bool {
+	t := k.threshold(candidate)
+	if len(candidate) < t.MinLength || shannonEntropy(candidate) < t.MinEntropy {
+		return false
+	}
+	lower, upper, digit := charClasses(trimHexPrefix(candidate))
+	if isHexToken(candidate) {
+		return digit && (lower || upper)
+	}
+	return lower && upper && digit
+}
+
+func (k entropyKind) Score(candidate []byte) int {
+	if shannonEntropy(candidate) < k.threshold(candidate).MinEntropy+entropyStrongMargin {
+		return -1
+	}
+	return 0
+}
+
+func (k entropyKind) threshold(token []byte) config.EntropyThreshold {
+	if isHexToken(token) {
+		return k.hex
+	}
+	return k.base64
+}
+
+// armoredRanges returns BEGIN/END armored blocks such as certificates.
+// Private key blocks have their own detector.
+func armoredRanges(text []byte) [][2]int {
+	var ranges [][2]int
+	for _, loc := range armorBegin.FindAllSubmatchIndex(text, -1) {
+		footer := []byte("-----END " + string(text[loc[2]:loc[3]]) + "-----")
+		i := bytes.Index(text[loc[1]:], footer)
+		if i < 0 {
+			continue
+		}
+		ranges = append(ranges, [2]int{loc[0], loc[1] + i + len(footer)})
+	}
+	return ranges
+}
+
+func inRanges(ranges [][2]int, offset int) bool {
+	for _, r := range ranges {
+		if offset >= r[0] && offset < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// isHashOutput reports whether the hex token at [start, end) is a git SHA
+// after "commit", a prefixed content digest, or the hash column of
+// sha256sum-style output.
+func isHashOutput(text []byte, start, end int) bool {
+	token := text[start:end]
+	if !isHexToken(token) {
+		return false
+	}
+	lineStart := bytes.LastIndexByte(text[:start], '\n') + 1
+	before := bytes.ToLower(text[lineStart:start])
+	if bytes.HasSuffix(bytes.TrimRight(before, " \t"), []byte("commit")) && len(before) > len(bytes.TrimRight(before, " \t")) {
+		return true
+	}
+	for _, prefix := range digestPrefixes {
+		if bytes.HasSuffix(before, prefix) {
+			return true
+		}
+	}
+	if lineStart == start && hashSumLengths[len(token)] {
+		rest := text[end:]
+		return bytes.HasPrefix(rest, []byte("  ")) || bytes.HasPrefix(rest, []byte(" *"))
+	}
+	return false
+}
+
+// shannonEntropy returns the entropy of token in bits per byte.
+func shannonEntropy(token []byte) float64 {
+	if len(token) == 0 {
+		return 0
+	}
+	var counts [256]int
+	for _, b := range token {
+		counts[b]++
+	}
+	n := float64(len(token))
+	entropy := 0.0
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+func charClasses(token []byte) (lower, upper, digit bool) {
+	for _, b := range token {
+		switch {
+		case b >= 'a' && b <= 'z':
+			lower = true
+		case b >= 'A' && b <= 'Z':
+			upper = true
+		case b >= '0' && b <= '9':
+			digit = true
+		}
+	}
+	return lower, upper, digit
+}
+
+// isPublicIdentifier reports whether token is an EVM address or a BIP32
+// extended public key.
+func isPublicIdentifier(token []byte) bool {
+	if has0xPrefix(token) && len(token) == 42 && isHex(token[2:]) {
+		return true
+	}
+	if len(token) == 111 && bytes.Equal(token[1:4], []byte("pub")) {
+		switch token[0] {
+		case 'x', 't', 'y', 'z':
+			return true
+		}
+	}
+	return false
+}
+
+// isHexToken reports whether token is hex, with or without a 0x prefix.
+func isHexToken(token []byte) bool {
+	token = trimHexPrefix(token)
+	return len(token) > 0 && isHex(token)
+}
+
+func trimHexPrefix(token []byte) []byte {
+	if has0xPrefix(token) {
+		return token[2:]
+	}
+	return token
+}
@@ -0,0 +1,74 @@
+package detect
+
+import (
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/config"
+)
+
+func entropyConfig() config.Config {
+	cfg := config.DefaultConfig()
+	cfg.Rulesets.SetEnabled(config.RulesetEntropy, true)
+	return cfg
+}
+
+func TestEntropyWeakTokenNeedsContext(t *testing.T) {
+	engine := newTestEngine(t, entropyConfig())
+	// 24 characters at about 4.25 bits: above the base64 threshold but
+	// inside the strong margin.
+	token := "Zk3mQ9xPw2LrT7vBn4HcZk3m"
+	if matches := engine.Find([]byte("value " + token)); len(matches) != 0 {
+		t.Fatalf("expected no match without context, got %+v", matches)
+	}
+	input := []byte("MY_SECRET=" + token)
+	matches := engine.Find(input)
+	if len(matches) != 1 || string(input[matches[0].Start:matches[0].End]) != token {
+		t.Fatalf("expected context match, got %+v", matches)
+	}
+}
+
+func TestEntropyStrongTokenWithoutContext(t *testing.T) {
+	engine := newTestEngine(t, entropyConfig())
+	token := "acwhPRqvX7DizWQ25iQtGe4Zjy5md80zE3VyXk0Q"
+	matches := engine.Find([]byte("value " + token))
+	if len(matches) != 1 || matches[0].RuleName != "high_entropy_string" {
+		t.Fatalf("expected entropy match, got %+v", matches)
+	}
+}
+
+func TestEntropyThresholdsAreConfigurable(t *testing.T) {
+	cfg := entropyConfig()
+	entropy := cfg.Rulesets[config.RulesetEntropy]
+	entropy.Base64.MinLength = 48
+	cfg.Rulesets[config.RulesetEntropy] = entropy
+	engine := newTestEngine(t, cfg)
+	if matches := engine.Find([]byte("secret acwhPRqvX7DizWQ25iQtGe4Zjy5md80zE3VyXk0Q")); len(matches) != 0 {
+		t.Fatalf("expected short token to be skipped, got %+v", matches)
+	}
+}
+
+func TestEntropySkipsKnownNonSecrets(t *testing.T) {
+	engine := newTestEngine(t, entropyConfig())
+	for _, input := range []string{
+		"commit c03d2ed726174a5841f78281469365503feac9e3",
+		"token 3f2b8c1e-9d4a-4e7b-b5c6-1a2b3c4d5e6f",
+		"e45027bb1d86624eb1dbef89a7f8fc8e157313c80dd5f1edd3bfc646fa5b557b  release.tar.gz",
+		"sha256:e45027bb1d86624eb1dbef89a7f8fc8e157313c80dd5f1edd3bfc646fa5b557b",
+		"to 0x742d35Cc6634C0532925a3b844Bc454e4438f44e",
+		"aaaaBBBB1111aaaaBBBB1111aaaaBBBB1111",
+	} {
+		if matches := engine.Find([]byte(input)); len(matches) != 0 {
+			t.Fatalf("%q: expected no matches, got %+v", input, matches)
+		}
+	}
+}
+
+func TestEntropyLosesOverlapToHigherSeverity(t *testing.T) {
+	cfg := entropyConfig()
+	cfg.Rulesets[config.RulesetWeb3] = config.Ruleset{Enabled: true, AllowBare64Hex: true}
+	engine := newTestEngine(t, cfg)
+	matches := engine.Find([]byte("e45027bb1d86624eb1dbef89a7f8fc8e157313c80dd5f1edd3bfc646fa5b557b"))
+	if len(matches) != 1 || matches[0].RuleName != "evm_private_key" {
+		t.Fatalf("expected evm_private_key to win, got %+v", matches)
+	}
+}
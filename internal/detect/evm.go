@@ -0,0 +1,77 @@
+package detect
+
+import (
+	"regexp"
+
+	"github.com/suryansh-23/secretty/internal/config"
+)
+
+var (
+	evmWithPrefix = regexp.MustCompile(`0x[0-9a-fA-F]{64}`)
+	evmBare       = regexp.MustCompile(`\b[0-9a-fA-F]{64}\b`)
+)
+
+// evmKind detects 32-byte hex EVM private keys. Bare keys without 0x are
+// only scanned when rulesets.web3.allow_bare_64hex is set.
+type evmKind struct {
+	allowBare bool
+}
+
+func newEvmKind(cfg config.Config) TypedKind {
+	web3 := cfg.Rulesets[config.RulesetWeb3]
+	return evmKind{allowBare: web3.Enabled && web3.AllowBare64Hex}
+}
+
+func (k evmKind) Scan(text []byte) [][2]int {
+	spans := regexSpans(evmWithPrefix, text)
+	if k.allowBare {
+		spans = append(spans, regexSpans(evmBare, text)...)
+	}
+	return spans
+}
+
+func (k evmKind) Validate(candidate []byte) bool {
+	return validateEvmPrivateKey(candidate, k.allowBare)
+}
+
+func (k evmKind) Score(candidate []byte) int {
+	if has0xPrefix(candidate) {
+		return 1
+	}
+	return 0
+}
+
+func validateEvmPrivateKey(token []byte, allowBare bool) bool {
+	if len(token) >= 2 && token[0] == '0' && (token[1] == 'x' || token[1] == 'X') {
+		return isHex(token[2:]) && len(token[2:]) == 64
+	}
+	if !allowBare {
+		return false
+	}
+	return len(token) == 64 && isHex(token)
+}
+
+func isHex(token []byte) bool {
+	for _, b := range token {
+		switch {
+		case b >= '0' && b <= '9':
+		case b >= 'a' && b <= 'f':
+		case b >= 'A' && b <= 'F':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func has0xPrefix(token []byte) bool {
+	return len(token) >= 2 && token[0] == '0' && (token[1] == 'x' || token[1] == 'X')
+}
+
+func regexSpans(re *regexp.Regexp, text []byte) [][2]int {
+	var spans [][2]int
+	for _, idx := range re.FindAllIndex(text, -1) {
+		spans = append(spans, [2]int{idx[0], idx[1]})
+	}
+	return spans
+}
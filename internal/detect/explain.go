@@ -0,0 +1,70 @@
+package detect
+
+import (
+	"sort"
+
+	"github.com/suryansh-23/secretty/internal/redact"
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+// Finding describes one candidate the engine considered.
+type Finding struct {
+	redact.Match
+	Severity types.Severity
+	// Source is "regex", "typed" or "known".
+	Source string
+	// Keywords reports whether the rule defines context keywords, and
+	// ContextHit whether one was found near the match.
+	Keywords   bool
+	ContextHit bool
+	// Encoding names the decode layers, such as "base64" or "json+percent",
+	// when the secret was found inside encoded text.
+	Encoding string
+	// Reason explains why a dropped candidate was rejected.
+	Reason string
+}
+
+// Explanation lists the matches Find would return and the candidates it
+// rejected along the way.
+type Explanation struct {
+	Kept    []Finding
+	Dropped []Finding
+}
+
+// Explain runs detection like Find and reports every decision it made.
+func (e *Engine) Explain(text []byte) Explanation {
+	ex := &Explanation{}
+	for _, cand := range e.resolve(text, ex) {
+		ex.Kept = append(ex.Kept, cand.finding(""))
+	}
+	sort.SliceStable(ex.Dropped, func(i, j int) bool {
+		return ex.Dropped[i].Start < ex.Dropped[j].Start
+	})
+	return *ex
+}
+
+func (ex *Explanation) drop(cand candidate, reason string) {
+	if ex == nil {
+		return
+	}
+	ex.Dropped = append(ex.Dropped, cand.finding(reason))
+}
+
+func (c candidate) finding(reason string) Finding {
+	source := "regex"
+	switch c.source {
+	case sourceTyped:
+		source = "typed"
+	case sourceKnown:
+		source = "known"
+	}
+	return Finding{
+		Match:      c.match,
+		Severity:   c.level,
+		Source:     source,
+		Keywords:   c.keywords,
+		ContextHit: c.contextHit,
+		Encoding:   c.encoding,
+		Reason:     reason,
+	}
+}
@@ -0,0 +1,49 @@
+package detect
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/config"
+)
+
+func TestExplainReportsOverlapDrops(t *testing.T) {
+	cfg := config.DefaultConfig()
+	engine := newTestEngine(t, cfg)
+	key := "0x" + strings.Repeat("a", 64)
+	ex := engine.Explain([]byte("PRIVATE_KEY=" + key))
+	if len(ex.Kept) != 1 || ex.Kept[0].RuleName != "evm_private_key" || ex.Kept[0].Source != "typed" {
+		t.Fatalf("unexpected kept findings: %+v", ex.Kept)
+	}
+	if !ex.Kept[0].ContextHit {
+		t.Fatalf("expected context keyword hit")
+	}
+	var found bool
+	for _, d := range ex.Dropped {
+		if d.RuleName == "env_private_key" {
+			found = true
+			if d.Reason != "overlaps evm_private_key (typed detector preferred over regex)" {
+				t.Fatalf("reason = %q", d.Reason)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("expected env_private_key drop, got %+v", ex.Dropped)
+	}
+}
+
+func TestExplainMatchesFind(t *testing.T) {
+	cfg := config.DefaultConfig()
+	engine := newTestEngine(t, cfg)
+	input := []byte("PRIVATE_KEY=0x" + strings.Repeat("b", 64) + " and 0x" + strings.Repeat("c", 64))
+	found := engine.Find(input)
+	ex := engine.Explain(input)
+	if len(found) != len(ex.Kept) {
+		t.Fatalf("find=%d explain=%d", len(found), len(ex.Kept))
+	}
+	for i := range found {
+		if found[i] != ex.Kept[i].Match {
+			t.Fatalf("match %d differs: %+v vs %+v", i, found[i], ex.Kept[i].Match)
+		}
+	}
+}
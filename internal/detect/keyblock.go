@@ -0,0 +1,60 @@
+package detect
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/suryansh-23/secretty/internal/config"
+)
+
+// keyBlockBegin matches the armor line of PEM, OpenSSH and PGP private keys.
+// Certificates and public keys use other labels and are left alone.
+var keyBlockBegin = regexp.MustCompile(`-----BEGIN ((?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?)-----`)
+
+// keyBlockKind detects armored private key blocks, BEGIN through END. A
+// block whose END line has not arrived within maxBytes of BEGIN fails
+// closed: everything after BEGIN, up to the bound, is matched, and the
+// stream keeps masking past it until the END line.
+type keyBlockKind struct {
+	maxBytes int
+}
+
+func newKeyBlockKind(cfg config.Config) TypedKind {
+	return keyBlockKind{maxBytes: cfg.Redaction.BlockMaxBytes}
+}
+
+func (k keyBlockKind) Scan(text []byte) [][2]int {
+	var spans [][2]int
+	offset := 0
+	for offset < len(text) {
+		loc := keyBlockBegin.FindSubmatchIndex(text[offset:])
+		if loc == nil {
+			break
+		}
+		start := offset + loc[0]
+		bodyStart := offset + loc[1]
+		limit := len(text)
+		if k.maxBytes > 0 && start+k.maxBytes < limit {
+			limit = start + k.maxBytes
+		}
+		end := limit
+		footer := []byte("-----END " + string(text[offset+loc[2]:offset+loc[3]]) + "-----")
+		if i := bytes.Index(text[bodyStart:limit], footer); i >= 0 {
+			end = bodyStart + i + len(footer)
+		}
+		spans = append(spans, [2]int{start, end})
+		offset = end
+	}
+	return spans
+}
+
+// Validate accepts any span starting with a BEGIN line; unterminated blocks
+// are matched on purpose.
+func (keyBlockKind) Validate(candidate []byte) bool {
+	loc := keyBlockBegin.FindIndex(candidate)
+	return loc != nil && loc[0] == 0
+}
+
+func (keyBlockKind) Score([]byte) int {
+	return 0
+}
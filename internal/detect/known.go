@@ -0,0 +1,217 @@
+package detect
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/suryansh-23/secretty/internal/config"
+	"github.com/suryansh-23/secretty/internal/redact"
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+const knownSecretRule = "known_secret"
+
+// KnownSecrets holds salted fingerprints of exact secret values. The values
+// themselves are never kept: a keyed rolling hash finds candidate windows and
+// an HMAC-SHA256 digest confirms them.
+type KnownSecrets struct {
+	salt    []byte
+	base    uint64
+	lengths []int
+	rolling map[int]map[uint64]struct{}
+	digests map[[sha256.Size]byte]struct{}
+}
+
+// LoadKnownSecrets reads values from the configured env vars, .env files and
+// value files. Values shorter than min_length are ignored, as are files that
+// do not exist.
+func LoadKnownSecrets(cfg config.KnownSecrets) (*KnownSecrets, error) {
+	salt := make([]byte, 40)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("known_secrets: salt: %w", err)
+	}
+	set := &KnownSecrets{
+		salt:    salt[:32],
+		base:    binary.LittleEndian.Uint64(salt[32:]) | 1,
+		rolling: map[int]map[uint64]struct{}{},
+		digests: map[[sha256.Size]byte]struct{}{},
+	}
+	add := func(value string) {
+		if len(value) < cfg.MinLength {
+			return
+		}
+		set.add([]byte(value))
+	}
+	for _, name := range cfg.Env {
+		add(os.Getenv(name))
+	}
+	for _, path := range cfg.EnvFiles {
+		values, err := readKnownFile(path, parseEnvValue)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			add(v)
+		}
+	}
+	for _, path := range cfg.Files {
+		values, err := readKnownFile(path, strings.TrimSpace)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			add(v)
+		}
+	}
+	for n := range set.rolling {
+		set.lengths = append(set.lengths, n)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(set.lengths)))
+	return set, nil
+}
+
+// Len returns the number of distinct values loaded.
+func (k *KnownSecrets) Len() int {
+	if k == nil {
+		return 0
+	}
+	return len(k.digests)
+}
+
+func (k *KnownSecrets) add(value []byte) {
+	digest := k.digest(value)
+	if _, dup := k.digests[digest]; dup {
+		return
+	}
+	k.digests[digest] = struct{}{}
+	if k.rolling[len(value)] == nil {
+		k.rolling[len(value)] = map[uint64]struct{}{}
+	}
+	k.rolling[len(value)][k.hash(value)] = struct{}{}
+}
+
+// Scan returns [start, end) spans of text equal to a known value. Each value
+// length costs one rolling pass over text; longer values are found first.
+func (k *KnownSecrets) Scan(text []byte) [][2]int {
+	if k == nil {
+		return nil
+	}
+	var spans [][2]int
+	for _, n := range k.lengths {
+		if n > len(text) {
+			continue
+		}
+		hashes := k.rolling[n]
+		// pow is base^(n-1), used to drop the outgoing byte.
+		pow := uint64(1)
+		for i := 1; i < n; i++ {
+			pow *= k.base
+		}
+		h := k.hash(text[:n])
+		for start := 0; ; start++ {
+			if _, ok := hashes[h]; ok {
+				if _, ok := k.digests[k.digest(text[start:start+n])]; ok {
+					spans = append(spans, [2]int{start, start + n})
+				}
+			}
+			if start+n >= len(text) {
+				break
+			}
+			h = (h-uint64(text[start])*pow)*k.base + uint64(text[start+n])
+		}
+	}
+	return spans
+}
+
+func (k *KnownSecrets) hash(value []byte) uint64 {
+	var h uint64
+	for _, b := range value {
+		h = h*k.base + uint64(b)
+	}
+	return h
+}
+
+func (k *KnownSecrets) digest(value []byte) [sha256.Size]byte {
+	mac := hmac.New(sha256.New, k.salt)
+	_, _ = mac.Write(value)
+	var out [sha256.Size]byte
+	copy(out[:], mac.Sum(nil))
+	return out
+}
+
+func (e *Engine) findKnownMatches(text []byte) []candidate {
+	if e.known == nil {
+		return nil
+	}
+	var out []candidate
+	for _, span := range e.known.Scan(text) {
+		out = append(out, candidate{
+			match: redact.Match{
+				Start:      span[0],
+				End:        span[1],
+				Action:     e.knownAction,
+				SecretType: types.SecretKnown,
+				RuleName:   knownSecretRule,
+			},
+			severity: severityRank(types.SeverityHigh),
+			level:    types.SeverityHigh,
+			source:   sourceKnown,
+			length:   span[1] - span[0],
+		})
+	}
+	return out
+}
+
+func readKnownFile(path string, value func(string) string) ([]string, error) {
+	data, err := os.ReadFile(config.ExpandHome(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("known_secrets: read %s: %w", path, err)
+	}
+	var values []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if v := value(line); v != "" {
+			values = append(values, v)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("known_secrets: read %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// parseEnvValue returns the value of a KEY=VALUE line from a .env file,
+// accepting an optional export prefix and surrounding quotes.
+func parseEnvValue(line string) string {
+	line = strings.TrimPrefix(line, "export ")
+	_, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return ""
+	}
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value
+}
@@ -0,0 +1,88 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/config"
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+func knownConfig(t *testing.T) config.Config {
+	t.Helper()
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, ".env")
+	valuesFile := filepath.Join(dir, "values")
+	writeFile(t, envFile, "# local\nexport DB_PASSWORD=\"tr0ub4dor&3\"\nSHORT=abc\nREDIS_URL=redis-pass-42 # cache\n")
+	writeFile(t, valuesFile, "plain-value-without-pattern\n\n")
+	t.Setenv("SECRETTY_TEST_KNOWN", "env-held-value-9")
+	cfg := config.DefaultConfig()
+	cfg.KnownSecrets.Enabled = true
+	cfg.KnownSecrets.Env = []string{"SECRETTY_TEST_KNOWN", "SECRETTY_TEST_UNSET"}
+	cfg.KnownSecrets.EnvFiles = []string{envFile, filepath.Join(dir, "missing.env")}
+	cfg.KnownSecrets.Files = []string{valuesFile}
+	return cfg
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestKnownSecretsLoadFromSources(t *testing.T) {
+	cfg := knownConfig(t)
+	known, err := LoadKnownSecrets(cfg.KnownSecrets)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if known.Len() != 4 {
+		t.Fatalf("loaded %d values, want 4", known.Len())
+	}
+}
+
+func TestKnownSecretsMatchAnywhere(t *testing.T) {
+	engine := newTestEngine(t, knownConfig(t))
+	values := []string{"tr0ub4dor&3", "redis-pass-42", "plain-value-without-pattern", "env-held-value-9"}
+	input := []byte("a=" + values[0] + " url=redis://:" + values[1] + "@host\n" + values[2] + "\nlast " + values[3])
+	matches := engine.Find(input)
+	if len(matches) != len(values) {
+		t.Fatalf("matches = %+v", matches)
+	}
+	for i, m := range matches {
+		if got := string(input[m.Start:m.End]); got != values[i] {
+			t.Fatalf("match %d = %q, want %q", i, got, values[i])
+		}
+		if m.RuleName != "known_secret" || m.SecretType != types.SecretKnown {
+			t.Fatalf("unexpected match %+v", m)
+		}
+	}
+	if matches := engine.Find([]byte("abc " + strings.ToUpper(values[2]))); len(matches) != 0 {
+		t.Fatalf("expected exact matching only, got %+v", matches)
+	}
+}
+
+func TestKnownSecretPreferredOverRegex(t *testing.T) {
+	cfg := knownConfig(t)
+	cfg.Rulesets.SetEnabled(config.RulesetPasswords, true)
+	engine := newTestEngine(t, cfg)
+	ex := engine.Explain([]byte("password=tr0ub4dor&3"))
+	if len(ex.Kept) != 1 || ex.Kept[0].Source != "known" {
+		t.Fatalf("expected known secret to win, got %+v", ex.Kept)
+	}
+	if len(ex.Dropped) != 1 || ex.Dropped[0].Reason != "overlaps known_secret (known secret preferred)" {
+		t.Fatalf("unexpected drops %+v", ex.Dropped)
+	}
+}
+
+func TestKnownSecretsUnreadableSourceFails(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.KnownSecrets.Enabled = true
+	cfg.KnownSecrets.Files = []string{t.TempDir()}
+	if _, err := NewEngine(cfg); err == nil {
+		t.Fatalf("expected read error for directory source")
+	}
+}
@@ -0,0 +1,88 @@
+package detect
+
+import (
+	"hash/crc32"
+	"regexp"
+
+	"github.com/suryansh-23/secretty/internal/config"
+)
+
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// crc32ChecksumLen is the width of a base62-encoded CRC32 token checksum.
+const crc32ChecksumLen = 6
+
+var (
+	// githubTokenPattern matches OAuth, user-to-server, server-to-server
+	// and refresh tokens (gho_, ghu_, ghs_, ghr_) and fine-grained
+	// github_pat_ tokens. Classic ghp_ tokens are left to the github_pat
+	// rule.
+	githubTokenPattern = regexp.MustCompile(`\b(?:gh[ousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9]{22}_[A-Za-z0-9]{59})\b`)
+	npmTokenPattern    = regexp.MustCompile(`\bnpm_[A-Za-z0-9]{36}\b`)
+)
+
+// githubTokenKind detects GitHub tokens. Prefixed tokens end in a base62
+// CRC32 of their 30 random characters, which must match. Fine-grained
+// tokens are validated by structure only: GitHub has not published how
+// their 82-character body is checksummed, so no CRC can be checked without
+// rejecting real tokens.
+type githubTokenKind struct{}
+
+func newGithubTokenKind(config.Config) TypedKind {
+	return githubTokenKind{}
+}
+
+func (githubTokenKind) Scan(text []byte) [][2]int {
+	return regexSpans(githubTokenPattern, text)
+}
+
+func (githubTokenKind) Validate(candidate []byte) bool {
+	if len(candidate) == 40 && candidate[3] == '_' {
+		return validCRC32Token(candidate[4:])
+	}
+	return len(candidate) == 93
+}
+
+func (githubTokenKind) Score([]byte) int {
+	return 0
+}
+
+// npmTokenKind detects npm access tokens, which use GitHub's checksummed
+// token format under the npm_ prefix.
+type npmTokenKind struct{}
+
+func newNpmTokenKind(config.Config) TypedKind {
+	return npmTokenKind{}
+}
+
+func (npmTokenKind) Scan(text []byte) [][2]int {
+	return regexSpans(npmTokenPattern, text)
+}
+
+func (npmTokenKind) Validate(candidate []byte) bool {
+	return len(candidate) == 40 && validCRC32Token(candidate[4:])
+}
+
+func (npmTokenKind) Score([]byte) int {
+	return 0
+}
+
+// validCRC32Token reports whether body ends in the zero-padded base62
+// encoding of the CRC32 (IEEE) of the characters before it.
+func validCRC32Token(body []byte) bool {
+	if len(body) <= crc32ChecksumLen {
+		return false
+	}
+	split := len(body) - crc32ChecksumLen
+	return string(body[split:]) == base62CRC32(body[:split])
+}
+
+func base62CRC32(data []byte) string {
+	sum := crc32.ChecksumIEEE(data)
+	out := make([]byte, crc32ChecksumLen)
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = base62Alphabet[sum%62]
+		sum /= 62
+	}
+	return string(out)
+}
@@ -0,0 +1,381 @@
+package detect
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/suryansh-23/secretty/internal/config"
+)
+
+var (
+	// assignmentLine matches a key at the start of a YAML, dotenv, TOML or
+	// HCL line: optional indent, YAML "- " or plan "+ "/"~ " markers and
+	// "export ", then the key and its separator. Group 1 is the key.
+	assignmentLine = regexp.MustCompile(`^[ \t]*(?:[-+~][ \t]+)?(?:export[ \t]+)?([A-Za-z_][A-Za-z0-9_.\-]*)[ \t]*[:=]`)
+	// blockHeader matches a YAML block scalar indicator and its modifiers.
+	blockHeader = regexp.MustCompile(`^[|>][-+0-9]*[ \t]*(?:#.*)?$`)
+	// heredocHeader matches an HCL or shell heredoc opener.
+	heredocHeader = regexp.MustCompile(`^<<-?[ \t]*["']?([A-Za-z_][A-Za-z0-9_]*)["']?[ \t]*$`)
+	// envString matches KEY=VALUE inside a JSON string, as in docker inspect.
+	envString = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)=(.+)$`)
+
+	// hclReferences are expressions that name a value rather than hold it.
+	hclReferences = [][]byte{[]byte("var."), []byte("local."), []byte("module."), []byte("data."), []byte("each.")}
+)
+
+// structuredKind masks the values of sensitive keys in JSON, YAML, dotenv,
+// TOML and HCL output. Key names are folded to snake_case (clientSecret,
+// client-secret and CLIENT_SECRET all become client_secret) and matched
+// against the rulesets.structured.keys globs. Every line of a multi-line
+// value is its own span, so line breaks and indentation stay visible.
+type structuredKind struct {
+	keys []string
+}
+
+func newStructuredKind(cfg config.Config) TypedKind {
+	set := cfg.Rulesets[config.RulesetStructured]
+	keys := make([]string, 0, len(set.Keys))
+	for _, key := range set.Keys {
+		keys = append(keys, strings.ToLower(key))
+	}
+	return structuredKind{keys: keys}
+}
+
+func (k structuredKind) Scan(text []byte) [][2]int {
+	s := &structuredScan{kind: k, text: text, sensitive: map[int]bool{}}
+	for start := 0; start < len(text); {
+		end := len(text)
+		if i := bytes.IndexByte(text[start:], '\n'); i >= 0 {
+			end = start + i
+		}
+		s.line(start, end)
+		start = end + 1
+	}
+	return s.spans
+}
+
+// Validate rejects empty values and placeholders such as $VAR, <secret>,
+// ****, Terraform's "(sensitive value)" and HCL references like var.x.
+func (structuredKind) Validate(candidate []byte) bool {
+	value := bytes.TrimSpace(candidate)
+	if len(value) == 0 {
+		return false
+	}
+	if value[0] == '$' || (value[0] == '<' && value[len(value)-1] == '>') {
+		return false
+	}
+	if bytes.HasPrefix(value, []byte("(sensitive")) {
+		return false
+	}
+	for _, ref := range hclReferences {
+		if bytes.HasPrefix(value, ref) {
+			return false
+		}
+	}
+	return len(bytes.Trim(value, "*x")) > 0
+}
+
+func (structuredKind) Score([]byte) int {
+	return 0
+}
+
+func (k structuredKind) sensitiveKey(name []byte) bool {
+	key := snakeKey(name)
+	for _, glob := range k.keys {
+		if ok, _ := path.Match(glob, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// snakeKey lowercases name, splits camelCase words and folds '-', '.' and
+// spaces to '_'.
+func snakeKey(name []byte) string {
+	var b strings.Builder
+	for i, c := range name {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			if i > 0 && (isLowerASCII(name[i-1]) || isDigitASCII(name[i-1])) {
+				b.WriteByte('_')
+			}
+			b.WriteByte(c + 'a' - 'A')
+		case c == '-' || c == '.' || c == ' ':
+			b.WriteByte('_')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// multiline is a value that continues past the line that opened it.
+type multiline int
+
+const (
+	multilineNone multiline = iota
+	multilineBlock
+	multilineQuoted
+	multilineHeredoc
+)
+
+// structuredScan is the state of one Scan. JSON nesting is tracked across
+// lines so a Terraform output object marked "sensitive": true has its
+// "value" masked whatever the output is called.
+type structuredScan struct {
+	kind  structuredKind
+	text  []byte
+	spans [][2]int
+
+	pending multiline
+	indent  int    // column of a block scalar's key
+	closer  string // closing quote or heredoc marker
+
+	depth      int
+	sensitive  map[int]bool
+	brackets   int
+	arrayDepth int
+}
+
+func (s *structuredScan) line(start, end int) {
+	for end > start && s.text[end-1] == '\r' {
+		end--
+	}
+	if s.pending != multilineNone && s.continued(start, end) {
+		return
+	}
+	if s.assignment(start, end) {
+		return
+	}
+	s.json(start, end)
+}
+
+func (s *structuredScan) add(start, end int) {
+	if end > start {
+		s.spans = append(s.spans, [2]int{start, end})
+	}
+}
+
+// continued consumes a line of a pending multi-line value and reports
+// whether the line belonged to it.
+func (s *structuredScan) continued(start, end int) bool {
+	line := s.text[start:end]
+	content := start + indentWidth(line)
+	switch s.pending {
+	case multilineBlock:
+		if content == end {
+			return true
+		}
+		if content-start > s.indent {
+			s.add(content, end)
+			return true
+		}
+	case multilineHeredoc:
+		if strings.TrimSpace(string(line)) == s.closer {
+			s.pending = multilineNone
+			return true
+		}
+		s.add(content, end)
+		return true
+	case multilineQuoted:
+		if i := closingQuote(line, 0, s.closer); i >= 0 {
+			s.add(start, start+i)
+			s.pending = multilineNone
+			return true
+		}
+		s.add(start, end)
+		return true
+	}
+	s.pending = multilineNone
+	return false
+}
+
+// assignment handles "key: value" and "key = value" lines. It reports
+// whether the line held a sensitive key.
+func (s *structuredScan) assignment(start, end int) bool {
+	line := s.text[start:end]
+	loc := assignmentLine.FindSubmatchIndex(line)
+	if loc == nil || !s.kind.sensitiveKey(line[loc[2]:loc[3]]) {
+		return false
+	}
+	sep := line[loc[1]-1]
+	v := loc[1]
+	if v < len(line) && line[v] == '=' {
+		return false
+	}
+	if sep == ':' && v < len(line) && line[v] != ' ' && line[v] != '\t' {
+		return false
+	}
+	v += indentWidth(line[v:])
+	value := line[v:]
+	switch {
+	case len(value) == 0, value[0] == '{', value[0] == '[', value[0] == '&', value[0] == '*', value[0] == '!':
+		return false
+	case sep == ':' && blockHeader.Match(value):
+		s.pending, s.indent = multilineBlock, loc[2]
+	case sep == '=' && heredocHeader.Match(value):
+		s.pending, s.closer = multilineHeredoc, string(heredocHeader.FindSubmatch(value)[1])
+	case bytes.HasPrefix(value, []byte(`"""`)), bytes.HasPrefix(value, []byte(`'''`)):
+		s.quoted(start+v+3, end, string(value[:3]))
+	case value[0] == '"' || value[0] == '\'':
+		s.quoted(start+v+1, end, string(value[:1]))
+	default:
+		s.add(start+v, start+v+bareValueLen(value, sep))
+	}
+	return true
+}
+
+// quoted masks a quoted value starting at from, continuing onto later lines
+// when it is not closed on this one.
+func (s *structuredScan) quoted(from, end int, closer string) {
+	if i := closingQuote(s.text[from:end], 0, closer); i >= 0 {
+		s.add(from, from+i)
+		return
+	}
+	s.add(from, end)
+	s.pending, s.closer = multilineQuoted, closer
+}
+
+// json walks JSON tokens on a line, masking values of sensitive keys,
+// scalars inside a sensitive array, and KEY=VALUE strings with a sensitive
+// KEY.
+func (s *structuredScan) json(start, end int) {
+	for i := start; i < end; i++ {
+		switch c := s.text[i]; {
+		case c == '"':
+			j := closingQuote(s.text[i+1:end], 0, `"`)
+			if j < 0 {
+				return
+			}
+			strStart, strEnd := i+1, i+1+j
+			i = strEnd
+			if k := strEnd + 1 + indentWidth(s.text[strEnd+1:end]); k < end && s.text[k] == ':' {
+				i = s.pair(s.text[strStart:strEnd], k+1+indentWidth(s.text[k+1:end]), end) - 1
+				continue
+			}
+			if s.arrayDepth > 0 {
+				s.add(strStart, strEnd)
+				continue
+			}
+			if m := envString.FindSubmatchIndex(s.text[strStart:strEnd]); m != nil && s.kind.sensitiveKey(s.text[strStart+m[2]:strStart+m[3]]) {
+				s.add(strStart+m[4], strStart+m[5])
+			}
+		case c == '{':
+			s.depth++
+		case c == '}':
+			delete(s.sensitive, s.depth)
+			s.depth = max(s.depth-1, 0)
+		case c == '[':
+			s.brackets++
+		case c == ']':
+			if s.arrayDepth == s.brackets {
+				s.arrayDepth = 0
+			}
+			s.brackets = max(s.brackets-1, 0)
+		case s.arrayDepth > 0 && (isDigitASCII(c) || c == '-'):
+			n := numberLen(s.text[i:end])
+			s.add(i, i+n)
+			i += n - 1
+		}
+	}
+}
+
+// pair handles the value of a JSON key starting at v and returns where
+// scanning resumes.
+func (s *structuredScan) pair(key []byte, v, end int) int {
+	value := s.text[v:end]
+	if string(key) == "sensitive" && bytes.HasPrefix(value, []byte("true")) {
+		s.sensitive[s.depth] = true
+		return v + 4
+	}
+	if !s.kind.sensitiveKey(key) && !(string(key) == "value" && s.sensitive[s.depth]) {
+		return v
+	}
+	switch {
+	case len(value) == 0:
+		return v
+	case value[0] == '"':
+		j := closingQuote(value[1:], 0, `"`)
+		if j < 0 {
+			s.add(v+1, end)
+			return end
+		}
+		s.add(v+1, v+1+j)
+		return v + j + 2
+	case value[0] == '[':
+		s.brackets++
+		s.arrayDepth = s.brackets
+		return v + 1
+	case isDigitASCII(value[0]) || value[0] == '-':
+		n := numberLen(value)
+		s.add(v, v+n)
+		return v + n
+	case value[0] == '{' || bytes.HasPrefix(value, []byte("true")) || bytes.HasPrefix(value, []byte("false")) || bytes.HasPrefix(value, []byte("null")):
+		return v
+	}
+	// A quoted YAML key with a plain value.
+	s.add(v, v+bareValueLen(value, ':'))
+	return end
+}
+
+// closingQuote returns the index in b, from i, of the closing quote, or -1.
+// Backslash escapes are honored inside double quotes.
+func closingQuote(b []byte, i int, quote string) int {
+	for i < len(b) {
+		if quote == `"` && b[i] == '\\' {
+			i += 2
+			continue
+		}
+		if bytes.HasPrefix(b[i:], []byte(quote)) {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+// bareValueLen is the length of an unquoted value: YAML plain scalars run to
+// a " #" comment, other values to the first blank. true, false, null and ~
+// are not values worth masking.
+func bareValueLen(value []byte, sep byte) int {
+	n := len(value)
+	if sep == ':' {
+		if i := bytes.Index(value, []byte(" #")); i >= 0 {
+			n = i
+		}
+		n = len(bytes.TrimRight(value[:n], " \t"))
+	} else if i := bytes.IndexAny(value, " \t"); i >= 0 {
+		n = i
+	}
+	switch strings.ToLower(string(value[:n])) {
+	case "true", "false", "null", "~":
+		return 0
+	}
+	return n
+}
+
+func numberLen(b []byte) int {
+	n := 0
+	for n < len(b) && (isDigitASCII(b[n]) || bytes.IndexByte([]byte("+-.eE"), b[n]) >= 0) {
+		n++
+	}
+	return n
+}
+
+func indentWidth(b []byte) int {
+	n := 0
+	for n < len(b) && (b[n] == ' ' || b[n] == '\t') {
+		n++
+	}
+	return n
+}
+
+func isLowerASCII(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+func isDigitASCII(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,101 @@
+package detect
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/config"
+)
+
+func structuredMatches(t *testing.T, cfg config.Config, input string) []string {
+	t.Helper()
+	var got []string
+	for _, m := range newTestEngine(t, cfg).Find([]byte(input)) {
+		if m.RuleName == "structured_value" {
+			got = append(got, input[m.Start:m.End])
+		}
+	}
+	return got
+}
+
+func structuredConfig() config.Config {
+	cfg := config.DefaultConfig()
+	cfg.Rulesets.SetEnabled(config.RulesetStructured, true)
+	return cfg
+}
+
+func TestStructuredValuesByFormat(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "json",
+			input: "{\n  \"username\": \"deploy\",\n  \"clientSecret\": \"s3cr3t-value\",\n  \"pin\": 4821,\n  \"token_type\": \"Bearer\"\n}\n",
+			want:  []string{"s3cr3t-value"},
+		},
+		{
+			name:  "compact json with escapes",
+			input: `{"access_token":"ya29.a\"b","expires_in":3599,"refresh_token":"1//0g"}` + "\n",
+			want:  []string{`ya29.a\"b`, "1//0g"},
+		},
+		{
+			name:  "terraform output",
+			input: "{\n  \"db\": {\n    \"sensitive\": true,\n    \"type\": \"string\",\n    \"value\": \"hunter2\"\n  },\n  \"url\": {\n    \"sensitive\": false,\n    \"value\": \"https://example.com\"\n  }\n}\n",
+			want:  []string{"hunter2"},
+		},
+		{
+			name:  "json array",
+			input: "\"token\": [\n  \"key-one\",\n  \"key-two\"\n],\n\"names\": [\"a\"]\n",
+			want:  []string{"key-one", "key-two"},
+		},
+		{
+			name:  "docker inspect env",
+			input: "\"Env\": [\n  \"PATH=/usr/bin\",\n  \"POSTGRES_PASSWORD=hunter2\"\n]\n",
+			want:  []string{"hunter2"},
+		},
+		{
+			name:  "yaml",
+			input: "data:\n  password: aHVudGVyMg== # base64\n  username: YWRtaW4=\nspec:\n  secretName: tls-cert\n",
+			want:  []string{"aHVudGVyMg=="},
+		},
+		{
+			name:  "yaml block scalar",
+			input: "- name: ci\n  private_key: |\n    line-one\n\n    line-two\n  user: bot\n",
+			want:  []string{"line-one", "line-two"},
+		},
+		{
+			name:  "dotenv",
+			input: "export API_KEY=abc123 # comment\nDB_PASSWORD=\"multi\nline\"\nPWD=/home/me\nAUTH_TOKEN=$TOKEN\n",
+			want:  []string{"abc123", "multi", "line"},
+		},
+		{
+			name:  "toml",
+			input: "[database]\npassword = '''\nfirst\nsecond'''\nport = 5432\n",
+			want:  []string{"first", "second"},
+		},
+		{
+			name:  "hcl",
+			input: "  password = var.db_password\n  private_key = <<-EOT\n    body\n  EOT\n  secret = \"literal\"\n  + token = (sensitive value)\n",
+			want:  []string{"body", "literal"},
+		},
+	}
+	for _, tc := range cases {
+		got := structuredMatches(t, structuredConfig(), tc.input)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestStructuredKeysAreConfigurable(t *testing.T) {
+	cfg := structuredConfig()
+	set := cfg.Rulesets[config.RulesetStructured]
+	set.Keys = []string{"license_*"}
+	cfg.Rulesets[config.RulesetStructured] = set
+	got := structuredMatches(t, cfg, "licenseKey: ABCD-1234\npassword: hunter2\n")
+	if !reflect.DeepEqual(got, []string{"ABCD-1234"}) {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -0,0 +1,74 @@
+package detect
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/suryansh-23/secretty/internal/config"
+)
+
+// Typed candidates are kept when their score reaches minTypedScore. A passing
+// validator is worth validScore and a nearby context keyword contextScore;
+// the kind's Score adds its own signals.
+const (
+	validScore    = 2
+	contextScore  = 1
+	minTypedScore = 2
+)
+
+// TypedKind implements one typed detector kind.
+type TypedKind interface {
+	// Scan returns candidate spans in text as [start, end) byte offsets.
+	Scan(text []byte) [][2]int
+	// Validate reports whether a candidate passes the kind's structural or
+	// checksum checks.
+	Validate(candidate []byte) bool
+	// Score returns kind-specific confidence for a candidate.
+	Score(candidate []byte) int
+}
+
+// KindFactory builds a kind for a config so kinds can read ruleset options.
+type KindFactory func(cfg config.Config) TypedKind
+
+var kindRegistry = map[string]KindFactory{}
+
+// RegisterKind adds a typed detector kind under name and makes the name
+// valid in config. It panics if name is already registered.
+func RegisterKind(name string, factory KindFactory) {
+	if _, dup := kindRegistry[name]; dup {
+		panic("detect: typed kind registered twice: " + name)
+	}
+	kindRegistry[name] = factory
+	config.RegisterTypedKind(name)
+}
+
+// Kinds returns the registered typed detector kinds, sorted.
+func Kinds() []string {
+	names := make([]string, 0, len(kindRegistry))
+	for name := range kindRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	RegisterKind(config.TypedKindEvmPrivateKey, newEvmKind)
+	RegisterKind(config.TypedKindBip39Mnemonic, newBip39Kind)
+	RegisterKind(config.TypedKindExtendedKey, newExtendedKeyKind)
+	RegisterKind(config.TypedKindWIFKey, newWifKind)
+	RegisterKind(config.TypedKindHighEntropy, newEntropyKind)
+	RegisterKind(config.TypedKindPrivateKeyBlock, newKeyBlockKind)
+	RegisterKind(config.TypedKindConnectionString, newConnStringKind)
+	RegisterKind(config.TypedKindGithubToken, newGithubTokenKind)
+	RegisterKind(config.TypedKindNpmToken, newNpmTokenKind)
+	RegisterKind(config.TypedKindStructuredValue, newStructuredKind)
+}
+
+func buildKind(cfg config.Config, det config.TypedDetector) (TypedKind, error) {
+	factory, ok := kindRegistry[det.Kind]
+	if !ok {
+		return nil, fmt.Errorf("typed detector %s: unknown kind %s", det.Name, det.Kind)
+	}
+	return factory(cfg), nil
+}
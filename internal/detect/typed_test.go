@@ -0,0 +1,79 @@
+package detect
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/config"
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+// digitsKind is a test kind: 8-digit tokens whose digits sum to a multiple of 10.
+type digitsKind struct{}
+
+func (digitsKind) Scan(text []byte) [][2]int {
+	var spans [][2]int
+	for i := 0; i+8 <= len(text); i++ {
+		if bytes.Equal(text[i:i+2], []byte("D:")) {
+			spans = append(spans, [2]int{i + 2, min(i+10, len(text))})
+		}
+	}
+	return spans
+}
+
+func (digitsKind) Validate(candidate []byte) bool {
+	sum := 0
+	for _, b := range candidate {
+		if b < '0' || b > '9' {
+			return false
+		}
+		sum += int(b - '0')
+	}
+	return len(candidate) == 8 && sum%10 == 0
+}
+
+func (digitsKind) Score([]byte) int { return 0 }
+
+func init() {
+	RegisterKind("TEST_DIGITS", func(config.Config) TypedKind { return digitsKind{} })
+}
+
+func TestRegisteredKindIsValidatedAndUsed(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.TypedDetectors = append(cfg.TypedDetectors, config.TypedDetector{
+		Name:       "test_digits",
+		Enabled:    true,
+		Kind:       "TEST_DIGITS",
+		Action:     types.ActionMask,
+		Severity:   types.SeverityHigh,
+		SecretType: types.SecretUnknown,
+		Ruleset:    "web3",
+	})
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("registered kind rejected: %v", err)
+	}
+	engine := newTestEngine(t, cfg)
+	if matches := engine.Find([]byte("D:12345678")); len(matches) != 0 {
+		t.Fatalf("expected checksum failure to be dropped, got %+v", matches)
+	}
+	matches := engine.Find([]byte("D:12345672"))
+	if len(matches) != 1 || matches[0].RuleName != "test_digits" {
+		t.Fatalf("expected test_digits match, got %+v", matches)
+	}
+}
+
+func TestBuiltinKindsAreRegistered(t *testing.T) {
+	for _, kind := range config.TypedKinds() {
+		if _, ok := kindRegistry[kind]; !ok {
+			t.Errorf("config lists kind %s that is not registered", kind)
+		}
+	}
+}
+
+func TestNewEngineRejectsUnknownKind(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.TypedDetectors[0].Kind = "NOPE"
+	if _, err := NewEngine(cfg); err == nil {
+		t.Fatalf("expected unknown kind error")
+	}
+}
@@ -9,11 +9,13 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/suryansh-23/secretty/internal/cache"
 	"github.com/suryansh-23/secretty/internal/clipboard"
 	"github.com/suryansh-23/secretty/internal/sessioncontrol"
+	"github.com/suryansh-23/secretty/internal/transcript"
 )
 
 const (
@@ -22,11 +24,20 @@ const (
 
 var ErrUnsupportedOperation = errors.New("unsupported operation")
 
+// ErrPolicyLocked reports that session policy forbids the requested operation.
+var ErrPolicyLocked = errors.New("operation locked by strict policy")
+
+const (
+	policyLockedError = "locked by strict policy"
+)
+
 type request struct {
 	Op       string `json:"op"`
 	ID       int    `json:"id,omitempty"`
 	Seconds  int64  `json:"seconds,omitempty"`
 	Commands int    `json:"commands,omitempty"`
+	Lines    int    `json:"lines,omitempty"`
+	Bytes    int    `json:"bytes,omitempty"`
 }
 
 type response struct {
@@ -79,19 +90,37 @@ type PauseStatus struct {
 	RemainingCommands int
 }
 
+// ServerOptions configures the handlers served for a session.
+type ServerOptions struct {
+	Cache  *cache.Cache
+	CopyFn func([]byte) error
+	Pause  *sessioncontrol.Controller
+	// Transcript holds sanitized session output served by export.
+	Transcript *transcript.Ring
+	// Locked refuses pause, copy, fetch, and reload operations until the
+	// server closes. The pause status can still be read and a pause ended.
+	Locked bool
+	// Reload re-reads configuration for the session.
+	Reload func() error
+}
+
 // Server serves IPC requests for a running session.
 type Server struct {
 	listener net.Listener
 	cache    *cache.Cache
 	copyFn   func([]byte) error
 	pause    *sessioncontrol.Controller
+	ring     *transcript.Ring
+	reload   func() error
+	locked   atomic.Bool
 }
 
 // StartServer starts a Unix socket server at path.
-func StartServer(path string, cache *cache.Cache, copyFn func([]byte) error, pause *sessioncontrol.Controller) (*Server, error) {
-	if cache == nil && pause == nil {
+func StartServer(path string, opts ServerOptions) (*Server, error) {
+	if opts.Cache == nil && opts.Pause == nil && opts.Transcript == nil && opts.Reload == nil && !opts.Locked {
 		return nil, errors.New("no ipc handlers available")
 	}
+	copyFn := opts.CopyFn
 	if copyFn == nil {
 		copyFn = func(payload []byte) error {
 			return clipboard.CopyBytes(string(clipboard.BackendAuto), payload)
@@ -105,7 +134,8 @@ func StartServer(path string, cache *cache.Cache, copyFn func([]byte) error, pau
 		_ = listener.Close()
 		return nil, err
 	}
-	server := &Server{listener: listener, cache: cache, copyFn: copyFn, pause: pause}
+	server := &Server{listener: listener, cache: opts.Cache, copyFn: copyFn, pause: opts.Pause, ring: opts.Transcript, reload: opts.Reload}
+	server.locked.Store(opts.Locked)
 	go server.serve()
 	return server, nil
 }
@@ -118,6 +148,15 @@ func (s *Server) Close() error {
 	return s.listener.Close()
 }
 
+// Lock refuses pause, copy, fetch, and reload operations from now on and ends
+// any active pause. A locked server cannot be unlocked.
+func (s *Server) Lock() {
+	s.locked.Store(true)
+	if s.pause != nil {
+		s.pause.Resume()
+	}
+}
+
 // TempSocketPath creates a unique socket path under the OS temp dir.
 func TempSocketPath() (string, error) {
 	dir := os.TempDir()
@@ -162,13 +201,7 @@ func CopyLast(socketPath string) (CopyResponse, error) {
 		return CopyResponse{}, err
 	}
 	if !resp.OK {
-		if resp.Error == "" {
-			return CopyResponse{}, errors.New("copy failed")
-		}
-		if resp.Error == "unknown operation" {
-			return CopyResponse{}, ErrUnsupportedOperation
-		}
-		return CopyResponse{}, errors.New(resp.Error)
+		return CopyResponse{}, responseError(resp, "copy failed")
 	}
 	return CopyResponse{ID: resp.ID, RuleName: resp.RuleName, Type: resp.Type, Label: resp.Label}, nil
 }
@@ -194,13 +227,7 @@ func FetchLast(socketPath string) ([]byte, CopyResponse, error) {
 		return nil, CopyResponse{}, err
 	}
 	if !resp.OK {
-		if resp.Error == "" {
-			return nil, CopyResponse{}, errors.New("copy failed")
-		}
-		if resp.Error == "unknown operation" {
-			return nil, CopyResponse{}, ErrUnsupportedOperation
-		}
-		return nil, CopyResponse{}, errors.New(resp.Error)
+		return nil, CopyResponse{}, responseError(resp, "copy failed")
 	}
 	payload, err := base64.StdEncoding.DecodeString(resp.Payload)
 	if err != nil {
@@ -230,13 +257,7 @@ func CopyByID(socketPath string, id int) (CopyResponse, error) {
 		return CopyResponse{}, err
 	}
 	if !resp.OK {
-		if resp.Error == "" {
-			return CopyResponse{}, errors.New("copy failed")
-		}
-		if resp.Error == "unknown operation" {
-			return CopyResponse{}, ErrUnsupportedOperation
-		}
-		return CopyResponse{}, errors.New(resp.Error)
+		return CopyResponse{}, responseError(resp, "copy failed")
 	}
 	return CopyResponse{ID: resp.ID, RuleName: resp.RuleName, Type: resp.Type, Label: resp.Label}, nil
 }
@@ -262,13 +283,7 @@ func FetchByID(socketPath string, id int) ([]byte, CopyResponse, error) {
 		return nil, CopyResponse{}, err
 	}
 	if !resp.OK {
-		if resp.Error == "" {
-			return nil, CopyResponse{}, errors.New("copy failed")
-		}
-		if resp.Error == "unknown operation" {
-			return nil, CopyResponse{}, ErrUnsupportedOperation
-		}
-		return nil, CopyResponse{}, errors.New(resp.Error)
+		return nil, CopyResponse{}, responseError(resp, "copy failed")
 	}
 	payload, err := base64.StdEncoding.DecodeString(resp.Payload)
 	if err != nil {
@@ -298,13 +313,7 @@ func ListSecrets(socketPath string) ([]SecretInfo, error) {
 		return nil, err
 	}
 	if !resp.OK {
-		if resp.Error == "" {
-			return nil, errors.New("list failed")
-		}
-		if resp.Error == "unknown operation" {
-			return nil, ErrUnsupportedOperation
-		}
-		return nil, errors.New(resp.Error)
+		return nil, responseError(resp, "list failed")
 	}
 	out := make([]SecretInfo, 0, len(resp.Records))
 	for _, rec := range resp.Records {
@@ -352,6 +361,64 @@ func PauseResume(socketPath string) (PauseStatus, error) {
 	return callPause(socketPath, request{Op: "pause-resume"})
 }
 
+// Export returns sanitized session output. When lines is positive the last
+// lines are returned; otherwise when bytes is positive the last bytes are
+// returned; otherwise the whole transcript is returned.
+func Export(socketPath string, lines, bytes int) ([]byte, error) {
+	conn, err := net.DialTimeout("unix", socketPath, defaultTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	if err := conn.SetDeadline(time.Now().Add(defaultTimeout)); err != nil {
+		return nil, err
+	}
+
+	enc := json.NewEncoder(conn)
+	dec := json.NewDecoder(conn)
+	if err := enc.Encode(request{Op: "export", Lines: lines, Bytes: bytes}); err != nil {
+		return nil, err
+	}
+	var resp response
+	if err := dec.Decode(&resp); err != nil {
+		return nil, err
+	}
+	if !resp.OK {
+		return nil, responseError(resp, "export failed")
+	}
+	payload, err := base64.StdEncoding.DecodeString(resp.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("decode payload: %w", err)
+	}
+	return payload, nil
+}
+
+// Reload asks the session to re-read its configuration.
+func Reload(socketPath string) error {
+	conn, err := net.DialTimeout("unix", socketPath, defaultTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+	if err := conn.SetDeadline(time.Now().Add(defaultTimeout)); err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(conn)
+	dec := json.NewDecoder(conn)
+	if err := enc.Encode(request{Op: "reload"}); err != nil {
+		return err
+	}
+	var resp response
+	if err := dec.Decode(&resp); err != nil {
+		return err
+	}
+	if !resp.OK {
+		return responseError(resp, "reload failed")
+	}
+	return nil
+}
+
 func callPause(socketPath string, req request) (PauseStatus, error) {
 	conn, err := net.DialTimeout("unix", socketPath, defaultTimeout)
 	if err != nil {
@@ -373,13 +440,7 @@ func callPause(socketPath string, req request) (PauseStatus, error) {
 		return PauseStatus{}, err
 	}
 	if !resp.OK {
-		if resp.Error == "" {
-			return PauseStatus{}, errors.New("pause operation failed")
-		}
-		if strings.EqualFold(resp.Error, "unknown operation") {
-			return PauseStatus{}, ErrUnsupportedOperation
-		}
-		return PauseStatus{}, errors.New(resp.Error)
+		return PauseStatus{}, responseError(resp, "pause operation failed")
 	}
 
 	mode := sessioncontrol.Mode(resp.PauseMode)
@@ -394,6 +455,19 @@ func callPause(socketPath string, req request) (PauseStatus, error) {
 	}, nil
 }
 
+func responseError(resp response, fallback string) error {
+	switch {
+	case resp.Error == "":
+		return errors.New(fallback)
+	case strings.EqualFold(resp.Error, "unknown operation"):
+		return ErrUnsupportedOperation
+	case resp.Error == policyLockedError:
+		return ErrPolicyLocked
+	default:
+		return errors.New(resp.Error)
+	}
+}
+
 func (s *Server) serve() {
 	for {
 		conn, err := s.listener.Accept()
@@ -422,6 +496,12 @@ func (s *Server) handle(conn net.Conn) {
 		}
 		return
 	}
+	if s.locked.Load() && lockedOperation(req.Op) {
+		if err := enc.Encode(response{OK: false, Error: policyLockedError}); err != nil {
+			return
+		}
+		return
+	}
 	switch req.Op {
 	case "fetch-last":
 		if s.cache == nil {
@@ -599,6 +679,41 @@ func (s *Server) handle(conn net.Conn) {
 		if err := enc.Encode(statusResponse(s.pause.Status())); err != nil {
 			return
 		}
+	case "export":
+		if s.ring == nil {
+			if err := enc.Encode(response{OK: false, Error: "transcript unavailable in this session"}); err != nil {
+				return
+			}
+			return
+		}
+		var data []byte
+		switch {
+		case req.Lines > 0:
+			data = s.ring.LastLines(req.Lines)
+		case req.Bytes > 0:
+			data = s.ring.LastBytes(req.Bytes)
+		default:
+			data = s.ring.Bytes()
+		}
+		if err := enc.Encode(response{OK: true, Payload: base64.StdEncoding.EncodeToString(data)}); err != nil {
+			return
+		}
+	case "reload":
+		if s.reload == nil {
+			if err := enc.Encode(response{OK: false, Error: "reload unavailable in this session"}); err != nil {
+				return
+			}
+			return
+		}
+		if err := s.reload(); err != nil {
+			if err := enc.Encode(response{OK: false, Error: err.Error()}); err != nil {
+				return
+			}
+			return
+		}
+		if err := enc.Encode(response{OK: true}); err != nil {
+			return
+		}
 	default:
 		if err := enc.Encode(response{OK: false, Error: "unknown operation"}); err != nil {
 			return
@@ -606,6 +721,19 @@ func (s *Server) handle(conn net.Conn) {
 	}
 }
 
+// lockedOperation reports whether op can pause redaction, release an
+// original secret, or change session policy. Reading the pause status and
+// ending a pause stay allowed.
+func lockedOperation(op string) bool {
+	if op == "list" || op == "reload" {
+		return true
+	}
+	if op == "pause-status" || op == "pause-resume" {
+		return false
+	}
+	return strings.HasPrefix(op, "pause-") || strings.HasPrefix(op, "copy-") || strings.HasPrefix(op, "fetch-")
+}
+
 func statusResponse(st sessioncontrol.Status) response {
 	resp := response{
 		OK:          true,
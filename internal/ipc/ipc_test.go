@@ -10,6 +10,7 @@ import (
 
 	"github.com/suryansh-23/secretty/internal/cache"
 	"github.com/suryansh-23/secretty/internal/sessioncontrol"
+	"github.com/suryansh-23/secretty/internal/transcript"
 	"github.com/suryansh-23/secretty/internal/types"
 )
 
@@ -27,7 +28,7 @@ func TestFetchLast(t *testing.T) {
 	if err != nil {
 		t.Fatalf("temp socket: %v", err)
 	}
-	server, err := StartServer(socketPath, store, func([]byte) error { return nil }, nil)
+	server, err := StartServer(socketPath, ServerOptions{Cache: store, CopyFn: func([]byte) error { return nil }})
 	if err != nil {
 		t.Fatalf("start server: %v", err)
 	}
@@ -60,7 +61,7 @@ func TestFetchByID(t *testing.T) {
 	if err != nil {
 		t.Fatalf("temp socket: %v", err)
 	}
-	server, err := StartServer(socketPath, store, func([]byte) error { return nil }, nil)
+	server, err := StartServer(socketPath, ServerOptions{Cache: store, CopyFn: func([]byte) error { return nil }})
 	if err != nil {
 		t.Fatalf("start server: %v", err)
 	}
@@ -87,7 +88,7 @@ func TestPauseLifecycle(t *testing.T) {
 	if err != nil {
 		t.Fatalf("temp socket: %v", err)
 	}
-	server, err := StartServer(socketPath, store, func([]byte) error { return nil }, ctrl)
+	server, err := StartServer(socketPath, ServerOptions{Cache: store, CopyFn: func([]byte) error { return nil }, Pause: ctrl})
 	if err != nil {
 		t.Fatalf("start server: %v", err)
 	}
@@ -125,7 +126,7 @@ func TestPauseUnsupportedOnLegacyServer(t *testing.T) {
 	if err != nil {
 		t.Fatalf("temp socket: %v", err)
 	}
-	server, err := StartServer(socketPath, store, func([]byte) error { return nil }, nil)
+	server, err := StartServer(socketPath, ServerOptions{Cache: store, CopyFn: func([]byte) error { return nil }})
 	if err != nil {
 		t.Fatalf("start server: %v", err)
 	}
@@ -181,7 +182,7 @@ func TestPauseWorksWithoutCopyCache(t *testing.T) {
 	if err != nil {
 		t.Fatalf("temp socket: %v", err)
 	}
-	server, err := StartServer(socketPath, nil, nil, ctrl)
+	server, err := StartServer(socketPath, ServerOptions{Pause: ctrl})
 	if err != nil {
 		t.Fatalf("start server: %v", err)
 	}
@@ -200,3 +201,133 @@ func TestPauseWorksWithoutCopyCache(t *testing.T) {
 		t.Fatal("expected copy fetch error without cache")
 	}
 }
+
+func TestLockedServerRefusesRevealOperations(t *testing.T) {
+	store := cache.New(10, time.Minute)
+	store.Put(cache.SecretRecord{
+		ID:       1,
+		Type:     types.SecretEvmPrivateKey,
+		RuleName: "env_private_key",
+		Original: []byte("secret"),
+	})
+	ctrl := sessioncontrol.NewController()
+	socketPath, err := TempSocketPath()
+	if err != nil {
+		t.Fatalf("temp socket: %v", err)
+	}
+	server, err := StartServer(socketPath, ServerOptions{Cache: store, Pause: ctrl, Locked: true})
+	if err != nil {
+		t.Fatalf("start server: %v", err)
+	}
+	defer func() { _ = server.Close() }()
+	defer func() { _ = os.Remove(socketPath) }()
+
+	if _, err := PauseFor(socketPath, time.Minute); !errors.Is(err, ErrPolicyLocked) {
+		t.Fatalf("pause for: expected ErrPolicyLocked, got %v", err)
+	}
+	if _, _, err := FetchLast(socketPath); !errors.Is(err, ErrPolicyLocked) {
+		t.Fatalf("fetch last: expected ErrPolicyLocked, got %v", err)
+	}
+	if _, err := CopyByID(socketPath, 1); !errors.Is(err, ErrPolicyLocked) {
+		t.Fatalf("copy id: expected ErrPolicyLocked, got %v", err)
+	}
+	if ctrl.IsPausedNow() {
+		t.Fatal("expected pause controller untouched")
+	}
+	if st, err := PauseStatusQuery(socketPath); err != nil || st.Active {
+		t.Fatalf("pause status: expected inactive status, got %+v %v", st, err)
+	}
+}
+
+func TestLockEndsActivePause(t *testing.T) {
+	ctrl := sessioncontrol.NewController()
+	socketPath, err := TempSocketPath()
+	if err != nil {
+		t.Fatalf("temp socket: %v", err)
+	}
+	server, err := StartServer(socketPath, ServerOptions{Pause: ctrl})
+	if err != nil {
+		t.Fatalf("start server: %v", err)
+	}
+	defer func() { _ = server.Close() }()
+	defer func() { _ = os.Remove(socketPath) }()
+
+	if st, err := PauseFor(socketPath, time.Minute); err != nil || !st.Active {
+		t.Fatalf("pause for: %+v %v", st, err)
+	}
+	server.Lock()
+	if ctrl.IsPausedNow() {
+		t.Fatal("expected locking to end the pause")
+	}
+	if st, err := PauseResume(socketPath); err != nil || st.Active {
+		t.Fatalf("pause resume while locked: %+v %v", st, err)
+	}
+	if _, err := PauseFor(socketPath, time.Minute); !errors.Is(err, ErrPolicyLocked) {
+		t.Fatalf("pause for: expected ErrPolicyLocked, got %v", err)
+	}
+}
+
+func TestExportTranscript(t *testing.T) {
+	ring := transcript.NewRing(0)
+	if _, err := ring.Write([]byte("one\ntwo\nthree\n")); err != nil {
+		t.Fatalf("write ring: %v", err)
+	}
+	socketPath, err := TempSocketPath()
+	if err != nil {
+		t.Fatalf("temp socket: %v", err)
+	}
+	server, err := StartServer(socketPath, ServerOptions{Transcript: ring, Locked: true})
+	if err != nil {
+		t.Fatalf("start server: %v", err)
+	}
+	defer func() { _ = server.Close() }()
+	defer func() { _ = os.Remove(socketPath) }()
+
+	data, err := Export(socketPath, 2, 0)
+	if err != nil {
+		t.Fatalf("export lines: %v", err)
+	}
+	if string(data) != "two\nthree\n" {
+		t.Fatalf("export lines = %q", string(data))
+	}
+	data, err = Export(socketPath, 0, 6)
+	if err != nil {
+		t.Fatalf("export bytes: %v", err)
+	}
+	if string(data) != "three\n" {
+		t.Fatalf("export bytes = %q", string(data))
+	}
+}
+
+func TestReloadReportsResult(t *testing.T) {
+	calls := 0
+	reloadErr := error(nil)
+	socketPath, err := TempSocketPath()
+	if err != nil {
+		t.Fatalf("temp socket: %v", err)
+	}
+	server, err := StartServer(socketPath, ServerOptions{Reload: func() error {
+		calls++
+		return reloadErr
+	}})
+	if err != nil {
+		t.Fatalf("start server: %v", err)
+	}
+	defer func() { _ = server.Close() }()
+	defer func() { _ = os.Remove(socketPath) }()
+
+	if err := Reload(socketPath); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	reloadErr = errors.New("config rejected: invalid config")
+	if err := Reload(socketPath); err == nil || err.Error() != "config rejected: invalid config" {
+		t.Fatalf("expected rejection, got %v", err)
+	}
+	server.Lock()
+	if err := Reload(socketPath); !errors.Is(err, ErrPolicyLocked) {
+		t.Fatalf("expected ErrPolicyLocked, got %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 reload calls, got %d", calls)
+	}
+}
@@ -0,0 +1,50 @@
+package ptywrap
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// foregroundWriter reports the command line of the PTY's foreground process
+// group leader before each output chunk in which it changed, so the observer
+// knows which command produced the output that follows.
+type foregroundWriter struct {
+	out      io.Writer
+	ptmx     *os.File
+	observer func(argv []string)
+	last     string
+}
+
+func (w *foregroundWriter) Write(p []byte) (int, error) {
+	if pgid, ok := foregroundGroup(w.ptmx); ok {
+		// The leader of a finished pipeline may be gone; keep the last
+		// command until the group changes to one that can be read.
+		if argv, err := commandLine(pgid); err == nil {
+			if key := strings.Join(argv, "\x00"); key != w.last {
+				w.last = key
+				w.observer(argv)
+			}
+		}
+	}
+	return w.out.Write(p)
+}
+
+// foregroundGroup returns the PTY's foreground process group (tcgetpgrp).
+// It goes through SyscallConn because Fd would switch ptmx to blocking mode.
+func foregroundGroup(ptmx *os.File) (int, bool) {
+	conn, err := ptmx.SyscallConn()
+	if err != nil {
+		return 0, false
+	}
+	pgid := 0
+	var ioctlErr error
+	if err := conn.Control(func(fd uintptr) {
+		pgid, ioctlErr = unix.IoctlGetInt(int(fd), unix.TIOCGPGRP)
+	}); err != nil || ioctlErr != nil || pgid <= 0 {
+		return 0, false
+	}
+	return pgid, true
+}
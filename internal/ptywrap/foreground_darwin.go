@@ -0,0 +1,41 @@
+//go:build darwin
+// +build darwin
+
+package ptywrap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// commandLine returns the argv of pid from the kern.procargs2 sysctl: a
+// 32-bit argc, the executable path, NUL padding, then argc arguments.
+func commandLine(pid int) ([]string, error) {
+	data, err := unix.SysctlRaw("kern.procargs2", pid)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 {
+		return nil, errors.New("short procargs")
+	}
+	argc := int(binary.LittleEndian.Uint32(data))
+	rest := data[4:]
+	end := bytes.IndexByte(rest, 0)
+	if end < 0 {
+		return nil, errors.New("malformed procargs")
+	}
+	rest = bytes.TrimLeft(rest[end:], "\x00")
+	argv := make([]string, 0, argc)
+	for len(argv) < argc && len(rest) > 0 {
+		arg, tail, _ := bytes.Cut(rest, []byte{0})
+		argv = append(argv, string(arg))
+		rest = tail
+	}
+	if len(argv) == 0 {
+		return nil, errors.New("empty command line")
+	}
+	return argv, nil
+}
@@ -0,0 +1,28 @@
+//go:build linux
+// +build linux
+
+package ptywrap
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strconv"
+)
+
+// commandLine returns the argv of pid from /proc/<pid>/cmdline.
+func commandLine(pid int) ([]string, error) {
+	data, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/cmdline")
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimRight(data, "\x00")
+	if len(data) == 0 {
+		return nil, errors.New("empty command line")
+	}
+	var argv []string
+	for _, arg := range bytes.Split(data, []byte{0}) {
+		argv = append(argv, string(arg))
+	}
+	return argv, nil
+}
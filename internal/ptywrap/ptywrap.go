@@ -28,6 +28,11 @@ type Options struct {
 	Output        io.Writer
 	Logger        *debug.Logger
 	InputObserver func([]byte)
+	// ResizeObserver receives the initial host size and every SIGWINCH resize.
+	ResizeObserver func(cols, rows int)
+	// ForegroundObserver receives the argv of the terminal's foreground
+	// command before the first output it writes.
+	ForegroundObserver func(argv []string)
 }
 
 // RunCommand starts cmd under a PTY and proxies IO.
@@ -68,7 +73,12 @@ func RunCommand(ctx context.Context, cmd *exec.Cmd, opts Options) (int, error) {
 			opts.Logger.Infof("ptywrap: inherit_size_failed=%v", err)
 		}
 	}
-	stopSignals := forwardSignals(cmd.Process, ptmx, isTTY, opts.Logger)
+	if opts.ResizeObserver != nil {
+		if winsize := hostWinsize(stdinFD, nil); winsize != nil {
+			opts.ResizeObserver(int(winsize.Cols), int(winsize.Rows))
+		}
+	}
+	stopSignals := forwardSignals(cmd.Process, ptmx, isTTY, opts.Logger, opts.ResizeObserver)
 	defer stopSignals()
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -76,7 +86,11 @@ func RunCommand(ctx context.Context, cmd *exec.Cmd, opts Options) (int, error) {
 
 	errCh := make(chan error, 1)
 	go copyInput(ctx, ptmx, os.Stdin, opts.Logger, opts.InputObserver)
-	go copyWithContext(ctx, out, ptmx, errCh)
+	var dst io.Writer = out
+	if opts.ForegroundObserver != nil {
+		dst = &foregroundWriter{out: out, ptmx: ptmx, observer: opts.ForegroundObserver}
+	}
+	go copyWithContext(ctx, dst, ptmx, errCh)
 
 	waitErr := cmd.Wait()
 	cancel()
@@ -382,7 +396,7 @@ func makeRawWithSignals(fd int) (func(), error) {
 	}, nil
 }
 
-func forwardSignals(proc *os.Process, ptmx *os.File, resize bool, logger *debug.Logger) func() {
+func forwardSignals(proc *os.Process, ptmx *os.File, resize bool, logger *debug.Logger, onResize func(cols, rows int)) func() {
 	if proc == nil {
 		return func() {}
 	}
@@ -400,6 +414,11 @@ func forwardSignals(proc *os.Process, ptmx *os.File, resize bool, logger *debug.
 					if err := pty.InheritSize(os.Stdin, ptmx); err != nil && logger != nil {
 						logger.Infof("ptywrap: resize_failed=%v", err)
 					}
+					if onResize != nil {
+						if winsize := hostWinsize(int(os.Stdin.Fd()), nil); winsize != nil {
+							onResize(int(winsize.Cols), int(winsize.Rows))
+						}
+					}
 				}
 			case syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTSTP:
 				if ptmx != nil {
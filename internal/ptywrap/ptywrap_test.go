@@ -20,3 +20,21 @@ func TestRunCommandExitCode(t *testing.T) {
 		t.Fatalf("exit code = %d, want 7", code)
 	}
 }
+
+func TestCommandLineReadsProcessArgs(t *testing.T) {
+	cmd := exec.Command("sleep", "5")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+	argv, err := commandLine(cmd.Process.Pid)
+	if err != nil {
+		t.Fatalf("command line: %v", err)
+	}
+	if len(argv) != 2 || argv[1] != "5" {
+		t.Fatalf("argv = %q", argv)
+	}
+}
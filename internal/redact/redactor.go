@@ -26,6 +26,7 @@ type Redactor struct {
 	lastGlowIndex  int
 	lastGlowBand   int
 	hasGlowHistory bool
+	maskWarn       bool
 }
 
 // NewRedactor returns a redactor using config defaults.
@@ -42,6 +43,15 @@ func NewRedactor(cfg config.Config) *Redactor {
 	return r
 }
 
+// maskingWarn returns a copy of r that masks matches it would otherwise only
+// highlight, for output that must never carry a secret, such as a transcript.
+// The copy shares r's stable hash salt so both render the same tokens.
+func (r *Redactor) maskingWarn() *Redactor {
+	masked := *r
+	masked.maskWarn = true
+	return &masked
+}
+
 // Apply replaces matches inside text and returns redacted output.
 func (r *Redactor) Apply(text []byte, matches []Match) ([]byte, error) {
 	if len(matches) == 0 {
@@ -69,20 +79,78 @@ func (r *Redactor) Apply(text []byte, matches []Match) ([]byte, error) {
 }
 
 func (r *Redactor) replacement(original []byte, match Match) []byte {
-	action := match.Action
-	if action == "" {
-		action = r.cfg.Redaction.DefaultAction
-	}
-	switch action {
+	switch r.actionFor(match) {
 	case types.ActionMask:
-		return r.maskBytes(original, match)
+		return r.maskLines(original, match)
 	case types.ActionPlaceholder:
 		return r.placeholder(match)
+	case types.ActionWarn:
+		return warnHighlight(original, r.cfg.Redaction.Warn.Highlight)
 	default:
 		return original
 	}
 }
 
+// actionFor resolves the effective action for a match. Warn mode overrides
+// every rule action so output is flagged but never hidden, while strict
+// no_reveal and a masking copy downgrade warn actions to masking.
+func (r *Redactor) actionFor(match Match) types.Action {
+	action := match.Action
+	if r.cfg.Mode == types.ModeWarn {
+		action = types.ActionWarn
+	} else if action == "" {
+		action = r.cfg.Redaction.DefaultAction
+	}
+	if action == types.ActionWarn && (r.maskWarn || r.cfg.NoRevealEnforced()) {
+		return types.ActionMask
+	}
+	return action
+}
+
+// warnHighlight wraps the original bytes in SGR sequences only, leaving the
+// visible text unchanged.
+func warnHighlight(original []byte, highlight types.WarnHighlight) []byte {
+	if len(original) == 0 {
+		return nil
+	}
+	var on, off string
+	switch highlight {
+	case types.WarnHighlightUnderline:
+		on, off = "\x1b[4m", "\x1b[24m"
+	case types.WarnHighlightBold:
+		on, off = "\x1b[1m", "\x1b[22m"
+	default:
+		on, off = "\x1b[7m", "\x1b[27m"
+	}
+	out := make([]byte, 0, len(on)+len(original)+len(off))
+	out = append(out, on...)
+	out = append(out, original...)
+	out = append(out, off...)
+	return out
+}
+
+// maskLines masks each line of a multi-line match separately so line breaks
+// survive and the masked text keeps its shape.
+func (r *Redactor) maskLines(original []byte, match Match) []byte {
+	if r.cfg.Masking.StableHashToken.Enabled || !bytes.ContainsAny(original, "\r\n") {
+		return r.maskBytes(original, match)
+	}
+	var out bytes.Buffer
+	for len(original) > 0 {
+		i := bytes.IndexAny(original, "\r\n")
+		if i < 0 {
+			out.Write(r.maskBytes(original, match))
+			break
+		}
+		if i > 0 {
+			out.Write(r.maskBytes(original[:i], match))
+		}
+		out.WriteByte(original[i])
+		original = original[i+1:]
+	}
+	return out.Bytes()
+}
+
 func (r *Redactor) maskBytes(original []byte, match Match) []byte {
 	if r.cfg.Masking.StableHashToken.Enabled {
 		return r.stableHashToken(match)
@@ -151,3 +151,67 @@ func glowParamsForTest(input []byte) (int, int) {
 	}
 	return idx, bandSize
 }
+
+func TestWarnActionHighlightsOriginal(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Mode = types.ModeDemo
+	cfg.Redaction.Warn.Highlight = types.WarnHighlightUnderline
+	r := NewRedactor(cfg)
+
+	out, err := r.Apply([]byte("key=secret"), []Match{{
+		Start:      4,
+		End:        10,
+		Action:     types.ActionWarn,
+		SecretType: types.SecretAPIKey,
+	}})
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if string(out) != "key=\x1b[4msecret\x1b[24m" {
+		t.Fatalf("output = %q", string(out))
+	}
+	if stripANSI(string(out)) != "key=secret" {
+		t.Fatalf("expected visible text unchanged")
+	}
+}
+
+func TestWarnModeOverridesRuleAction(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Mode = types.ModeWarn
+	r := NewRedactor(cfg)
+
+	out, err := r.Apply([]byte("secret"), []Match{{
+		Start:      0,
+		End:        6,
+		Action:     types.ActionMask,
+		SecretType: types.SecretAPIKey,
+	}})
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if string(out) != "\x1b[7msecret\x1b[27m" {
+		t.Fatalf("output = %q", string(out))
+	}
+}
+
+func TestStrictNoRevealMasksWarnAction(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Mode = types.ModeStrict
+	cfg.Strict.NoReveal = true
+	cfg.Masking.Style = types.MaskStyleBlock
+	cfg.Masking.BlockChar = "#"
+	r := NewRedactor(cfg)
+
+	out, err := r.Apply([]byte("secret"), []Match{{
+		Start:      0,
+		End:        6,
+		Action:     types.ActionWarn,
+		SecretType: types.SecretAPIKey,
+	}})
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if string(out) != "######" {
+		t.Fatalf("output = %q", string(out))
+	}
+}
@@ -2,8 +2,10 @@ package redact
 
 import (
 	"bytes"
+	"crypto/subtle"
 	"io"
 	"regexp"
+	"sync"
 	"time"
 	"unicode/utf8"
 
@@ -17,12 +19,17 @@ import (
 
 // Stream applies redaction to a byte stream and writes to an output.
 type Stream struct {
-	out        io.Writer
+	mu         sync.Mutex
+	screen     io.Writer
+	transcript io.Writer
 	tokenizer  *ansi.Tokenizer
 	detector   Detector
 	redactor   *Redactor
 	windowSize int
 	buffer     []byte
+	// escapes holds escape sequences that arrived after text still in
+	// buffer, so they are written in order with it.
+	escapes    []heldEscape
 	plainTail  []byte
 	plainTailN int
 	cache      *cache.Cache
@@ -32,11 +39,59 @@ type Stream struct {
 	strictMode bool
 	logger     *debug.Logger
 
+	// transcriptRedactor renders what the transcript receives: the same as
+	// the screen, except that warned secrets are masked.
+	transcriptRedactor *Redactor
+
 	statusEnabled   bool
 	statusRateLimit time.Duration
 	lastStatus      time.Time
 	altScreen       bool
 	pauseGate       PauseGate
+	pauseBlocked    bool
+	bypass          bool
+
+	// command is the command line the shell last reported starting, until
+	// it reports the command ended. commandSettings returns the settings for
+	// that command's output, which replace the session's cfg and sessionDetector
+	// while commandActive. Only marks carrying markToken are honored, so
+	// command output cannot forge them.
+	markToken           string
+	command             string
+	commandSettings     func(command string) *CommandSettings
+	commandActive       bool
+	commandBypassed     bool
+	commandPauseBlocked bool
+	cfg                 config.Config
+	sessionDetector     Detector
+
+	// openBlock is a private key block that ran past
+	// redaction.block_max_bytes without its END line. Output stays masked
+	// as part of it until an END line, a shell mark, or the end of the
+	// stream.
+	openBlock *Match
+
+	dirObserver  func(host, dir string)
+	reportedDir  string
+	reportedHost string
+}
+
+// heldEscape is an escape sequence that follows the first at bytes of the
+// rolling buffer.
+type heldEscape struct {
+	at    int
+	bytes []byte
+}
+
+// CommandSettings are the redaction settings for the output of one command a
+// shell reports.
+type CommandSettings struct {
+	Config   config.Config
+	Detector Detector
+	// Bypass passes the command's output through unredacted.
+	Bypass bool
+	// PauseBlocked keeps redaction on while the command runs.
+	PauseBlocked bool
 }
 
 // PauseGate reports whether redaction should currently be paused.
@@ -57,68 +112,183 @@ func NewStream(out io.Writer, cfg config.Config, detector Detector, secretCache
 	if windowSize == 0 {
 		plainTailN = 8192
 	}
+	s := &Stream{
+		screen:     out,
+		tokenizer:  &ansi.Tokenizer{},
+		windowSize: windowSize,
+		cache:      secretCache,
+		plainTailN: plainTailN,
+		logger:     logger,
+		pauseGate:  pauseGate,
+	}
+	s.cfg, s.sessionDetector = cfg, detector
+	s.applyConfig(cfg, detector)
+	return s
+}
+
+// Reconfigure swaps the detector, redaction settings, and bypass state in one
+// step. Buffered output is redacted with the new settings; the window size is
+// fixed for the stream.
+func (s *Stream) Reconfigure(cfg config.Config, detector Detector, bypass bool) {
+	if detector == nil {
+		detector = NoopDetector{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg, s.sessionDetector = cfg, detector
+	s.applyConfig(cfg, detector)
+	s.commandActive = false
+	s.bypass = bypass
+	s.applyCommandSettings()
+}
+
+// SetBypass passes output through unredacted, as for allowlisted commands.
+// Bypassed output is never copied to the transcript.
+func (s *Stream) SetBypass(bypass bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bypass = bypass
+}
+
+// SetPauseBlocked keeps redaction on while blocked, even when the pause gate
+// reports an active pause.
+func (s *Stream) SetPauseBlocked(blocked bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pauseBlocked = blocked
+}
+
+func (s *Stream) applyConfig(cfg config.Config, detector Detector) {
 	cacheOn := cfg.Overrides.CopyWithoutRender.Enabled
 	if cfg.Mode == types.ModeStrict && cfg.Strict.DisableCopyOriginal {
 		cacheOn = false
 	}
-	statusEnabled := cfg.Redaction.StatusLine.Enabled
-	statusRateLimit := time.Duration(cfg.Redaction.StatusLine.RateLimitMS) * time.Millisecond
-	return &Stream{
-		out:             out,
-		tokenizer:       &ansi.Tokenizer{},
-		detector:        detector,
-		redactor:        NewRedactor(cfg),
-		windowSize:      windowSize,
-		cache:           secretCache,
-		plainTailN:      plainTailN,
-		cacheOn:         cacheOn,
-		includeID:       cfg.Redaction.IncludeEventID,
-		strictMode:      cfg.Mode == types.ModeStrict,
-		logger:          logger,
-		statusEnabled:   statusEnabled,
-		statusRateLimit: statusRateLimit,
-		pauseGate:       pauseGate,
-	}
+	s.detector = detector
+	s.redactor = NewRedactor(cfg)
+	s.transcriptRedactor = s.redactor.maskingWarn()
+	s.cacheOn = cacheOn
+	s.includeID = cfg.Redaction.IncludeEventID
+	s.strictMode = cfg.Mode == types.ModeStrict
+	s.statusEnabled = cfg.Redaction.StatusLine.Enabled
+	s.statusRateLimit = time.Duration(cfg.Redaction.StatusLine.RateLimitMS) * time.Millisecond
+}
+
+// SetTranscript tees redacted output to w. Secrets the screen only warns
+// about are masked in the copy, and output written while redaction is paused
+// or bypassed is never copied to the transcript.
+func (s *Stream) SetTranscript(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transcript = w
+}
+
+// SetCommandSettings registers fn to return the settings for the output of
+// each command a shell inside the session reports with OSC 133 marks, or nil
+// to keep the session's. The settings apply from the command's start mark to
+// its end mark or the next prompt. fn runs under the stream's lock.
+func (s *Stream) SetCommandSettings(fn func(command string) *CommandSettings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commandSettings = fn
+	s.applyCommandSettings()
+}
+
+// SetMarkToken sets the token the session's shell hooks add to their OSC 133
+// marks. Marks without it are passed on but otherwise ignored; with no token
+// set, every mark is ignored.
+func (s *Stream) SetMarkToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.markToken = token
+}
+
+// Command returns the command line of the shell command whose output the
+// stream is processing, or "" at the prompt or when the shell reports none.
+func (s *Stream) Command() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.command
+}
+
+// SetDirObserver registers fn to receive the working directory a shell
+// reports with OSC 7. fn runs after the write that carried the report, outside
+// the stream's lock, so it may call Reconfigure.
+func (s *Stream) SetDirObserver(fn func(host, dir string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirObserver = fn
 }
 
 // Write processes input bytes and writes redacted output.
 func (s *Stream) Write(p []byte) (int, error) {
-	if s.isPaused() {
-		if err := s.flushBufferedRedacted(); err != nil {
+	s.mu.Lock()
+	n, err := s.write(p)
+	observer, host, dir := s.dirObserver, s.reportedHost, s.reportedDir
+	s.reportedHost, s.reportedDir = "", ""
+	s.mu.Unlock()
+	if observer != nil && dir != "" {
+		observer(host, dir)
+	}
+	return n, err
+}
+
+func (s *Stream) write(p []byte) (int, error) {
+	segments := s.tokenizer.Push(p)
+	// A semantic prompt mark can switch the bypass for what follows it, so
+	// each run of segments up to and including a mark is handled on its own.
+	for len(segments) > 0 {
+		n := len(segments)
+		for i, seg := range segments {
+			if seg.Kind == ansi.SegmentEscape && bytes.HasPrefix(seg.Bytes, []byte("\x1b]133;")) {
+				n = i + 1
+				break
+			}
+		}
+		if err := s.writeSegments(segments[:n]); err != nil {
 			return 0, err
 		}
+		segments = segments[n:]
+	}
+	return len(p), nil
+}
+
+func (s *Stream) writeSegments(segments []ansi.Segment) error {
+	if s.bypass || s.commandBypassed || s.isPaused() {
+		if err := s.flushBufferedRedacted(); err != nil {
+			return err
+		}
 		s.plainTail = nil
-		segments := s.tokenizer.Push(p)
 		for _, seg := range segments {
+			chunk := seg.Bytes
 			if seg.Kind == ansi.SegmentEscape {
-				s.updateAltScreen(seg.Bytes)
+				chunk = s.observeEscape(seg.Bytes)
 			}
-			if _, err := s.out.Write(seg.Bytes); err != nil {
-				return 0, err
+			if _, err := s.screen.Write(chunk); err != nil {
+				return err
 			}
 		}
-		return len(p), nil
+		return nil
 	}
-	segments := s.tokenizer.Push(p)
 	if s.windowSize == 0 {
-		if err := s.writeInteractiveSegments(segments); err != nil {
-			return 0, err
-		}
-		return len(p), nil
+		return s.writeInteractiveSegments(segments)
 	}
 	for _, seg := range segments {
 		if seg.Kind == ansi.SegmentEscape {
-			s.updateAltScreen(seg.Bytes)
-			if _, err := s.out.Write(seg.Bytes); err != nil {
-				return 0, err
+			esc := s.observeEscape(seg.Bytes)
+			if len(s.buffer) > 0 {
+				s.escapes = append(s.escapes, heldEscape{at: len(s.buffer), bytes: esc})
+				continue
+			}
+			if err := s.writeOut(esc); err != nil {
+				return err
 			}
 			continue
 		}
 		if err := s.processText(seg.Bytes); err != nil {
-			return 0, err
+			return err
 		}
 	}
-	return len(p), nil
+	return nil
 }
 
 // Close flushes any pending data.
@@ -126,19 +296,37 @@ func (s *Stream) Close() error {
 	return s.Flush()
 }
 
-// Flush drains tokenizer and rolling buffer.
+// Flush drains the rolling buffer and tokenizer. An unterminated escape
+// sequence left in the tokenizer was never redacted, so it only reaches the
+// screen.
 func (s *Stream) Flush() error {
-	segments := s.tokenizer.Flush()
-	for _, seg := range segments {
-		if _, err := s.out.Write(seg.Bytes); err != nil {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.windowSize == 0 {
+		s.plainTail = nil
+	} else if err := s.flushBufferedRedacted(); err != nil {
+		return err
+	}
+	for _, seg := range s.tokenizer.Flush() {
+		if _, err := s.screen.Write(seg.Bytes); err != nil {
 			return err
 		}
 	}
+	return nil
+}
+
+// Release writes buffered output up to the last complete line, keeping back
+// only a trailing partial line and any match that runs to the end of the
+// buffer, which later output could still extend. Callers that attribute
+// output to the write that produced it, such as an asciicast scrub, call it
+// after each write.
+func (s *Stream) Release() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.windowSize == 0 {
-		s.plainTail = nil
 		return nil
 	}
-	return s.flushBufferedRedacted()
+	return s.emitBuffered(bytes.LastIndexByte(s.buffer, '\n') + 1)
 }
 
 func (s *Stream) flushBufferedRedacted() error {
@@ -147,22 +335,92 @@ func (s *Stream) flushBufferedRedacted() error {
 	}
 	matches := s.detector.Find(s.buffer)
 	matches = s.assignIDs(matches)
+	block, closed := s.findOpenBlock(s.buffer, 0, matches)
+	s.setOpenBlock(block, closed)
 	s.storeMatches(s.buffer, matches)
-	redacted, err := s.redactor.Apply(s.buffer, matches)
+	redacted, err := s.writeHeld(s.buffer, withBlock(matches, block), s.escapes)
 	if err != nil {
 		return err
 	}
-	if _, err := s.out.Write(redacted); err != nil {
-		return err
-	}
 	s.logMatches(matches)
 	s.maybeEmitStatus(matches, redacted)
-	s.buffer = nil
+	s.buffer, s.escapes = nil, nil
 	return nil
 }
 
+// writeHeld writes text with matches redacted, and the escapes held with it
+// at their offsets. It returns what the screen received for the text.
+func (s *Stream) writeHeld(text []byte, matches []Match, escapes []heldEscape) ([]byte, error) {
+	if len(escapes) == 0 {
+		return s.writeRedacted(text, matches)
+	}
+	infos := make([]segmentInfo, 0, len(escapes)+1)
+	start := 0
+	for _, esc := range escapes {
+		infos = append(infos, segmentInfo{index: len(infos), start: start, end: esc.at})
+		start = esc.at
+	}
+	infos = append(infos, segmentInfo{index: len(infos), start: start, end: len(text)})
+	matchesBySeg := splitMatchesBySegment(matches, infos)
+	var redacted []byte
+	for i, info := range infos {
+		if i > 0 {
+			if err := s.writeOut(escapes[i-1].bytes); err != nil {
+				return nil, err
+			}
+		}
+		if info.end == info.start {
+			continue
+		}
+		chunk, err := s.writeRedacted(text[info.start:info.end], matchesBySeg[i])
+		if err != nil {
+			return nil, err
+		}
+		redacted = append(redacted, chunk...)
+	}
+	return redacted, nil
+}
+
+// writeOut writes b unchanged to the screen and the transcript.
+func (s *Stream) writeOut(b []byte) error {
+	if _, err := s.screen.Write(b); err != nil {
+		return err
+	}
+	if s.transcript == nil {
+		return nil
+	}
+	_, err := s.transcript.Write(b)
+	return err
+}
+
+// writeRedacted writes text with matches redacted to the screen and the
+// transcript, and returns what the screen received. A warned secret is shown
+// on screen but masked in the transcript.
+func (s *Stream) writeRedacted(text []byte, matches []Match) ([]byte, error) {
+	redacted, err := s.redactor.Apply(text, matches)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.screen.Write(redacted); err != nil {
+		return nil, err
+	}
+	if s.transcript == nil {
+		return redacted, nil
+	}
+	masked := redacted
+	if s.anyWarn(matches) {
+		if masked, err = s.transcriptRedactor.Apply(text, matches); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := s.transcript.Write(masked); err != nil {
+		return nil, err
+	}
+	return redacted, nil
+}
+
 func (s *Stream) isPaused() bool {
-	return s.pauseGate != nil && s.pauseGate.IsPausedNow()
+	return !s.pauseBlocked && !s.commandPauseBlocked && s.pauseGate != nil && s.pauseGate.IsPausedNow()
 }
 
 func (s *Stream) processText(text []byte) error {
@@ -171,30 +429,56 @@ func (s *Stream) processText(text []byte) error {
 	if len(s.buffer) > s.windowSize {
 		emitLen = len(s.buffer) - s.windowSize
 	}
+	return s.emitBuffered(emitLen)
+}
+
+// emitBuffered writes up to emitLen bytes of the rolling buffer, fewer when a
+// match or multi-byte rune would be split, with the escapes held among them.
+func (s *Stream) emitBuffered(emitLen int) error {
 	if emitLen == 0 {
 		return nil
 	}
 	matches := s.detector.Find(s.buffer)
 	emitLen = safeEmitLen(emitLen, matches)
+	block, closed := s.findOpenBlock(s.buffer, 0, matches)
+	if block != nil && block.Start < emitLen && block.End > emitLen {
+		// Emit whole lines of an open block so its END line is never
+		// split across writes.
+		if i := bytes.LastIndexByte(s.buffer[block.Start:emitLen], '\n'); i >= 0 {
+			emitLen = block.Start + i + 1
+		}
+		block.End, closed = emitLen, false
+	}
 	emitLen = utf8SafePrefixLen(s.buffer, emitLen)
 	if emitLen == 0 {
 		return nil
 	}
+	if block != nil && block.Start < emitLen {
+		block.End = min(block.End, emitLen)
+		s.setOpenBlock(block, closed)
+	} else {
+		block = nil
+	}
 	emitBuf := s.buffer[:emitLen]
 	keepBuf := s.buffer[emitLen:]
 	emitMatches := filterMatches(matches, emitLen)
 	emitMatches = s.assignIDs(emitMatches)
 	s.storeMatches(emitBuf, emitMatches)
-	redacted, err := s.redactor.Apply(emitBuf, emitMatches)
-	if err != nil {
-		return err
+	held := 0
+	for held < len(s.escapes) && s.escapes[held].at <= emitLen {
+		held++
 	}
-	if _, err := s.out.Write(redacted); err != nil {
+	redacted, err := s.writeHeld(emitBuf, withBlock(emitMatches, block), s.escapes[:held])
+	if err != nil {
 		return err
 	}
 	s.logMatches(emitMatches)
 	s.maybeEmitStatus(emitMatches, redacted)
 	s.buffer = append([]byte(nil), keepBuf...)
+	s.escapes = append([]heldEscape(nil), s.escapes[held:]...)
+	for i := range s.escapes {
+		s.escapes[i].at -= emitLen
+	}
 	return nil
 }
 
@@ -226,35 +510,23 @@ func (s *Stream) writeInteractiveSegments(segments []ansi.Segment) error {
 	infoIdx := 0
 	for _, seg := range segments {
 		if seg.Kind == ansi.SegmentEscape {
-			s.updateAltScreen(seg.Bytes)
-			if _, err := s.out.Write(seg.Bytes); err != nil {
+			if err := s.writeOut(s.observeEscape(seg.Bytes)); err != nil {
 				return err
 			}
 			continue
 		}
-		var chunk []byte
+		var segMatches []Match
 		if infoIdx < len(infos) {
+			segMatches = matchesBySeg[infoIdx]
 			infoIdx++
-			segMatches := matchesBySeg[infoIdx-1]
-			if len(segMatches) == 0 {
-				chunk = seg.Bytes
-			} else {
-				redacted, err := s.redactor.Apply(seg.Bytes, segMatches)
-				if err != nil {
-					return err
-				}
-				chunk = redacted
-			}
-		} else {
-			chunk = seg.Bytes
 		}
-		if len(chunk) == 0 {
+		if len(seg.Bytes) == 0 {
 			continue
 		}
-		if _, err := s.out.Write(chunk); err != nil {
+		chunk, err := s.writeRedacted(seg.Bytes, segMatches)
+		if err != nil {
 			return err
 		}
-		segMatches := matchesBySeg[infoIdx-1]
 		s.maybeEmitStatus(segMatches, chunk)
 	}
 	if len(cacheMatches) > 0 && len(cacheText) > 0 {
@@ -271,31 +543,113 @@ func (s *Stream) findInteractiveMatches(plain []byte, infos []segmentInfo) (map[
 		combined = append(append([]byte(nil), tail...), plain...)
 	}
 	matches := s.detector.Find(combined)
-	if len(matches) == 0 {
-		s.updatePlainTail(combined)
+	block, closed := s.findOpenBlock(combined, len(tail), matches)
+	s.setOpenBlock(block, closed)
+	if len(matches) == 0 && block == nil {
+		s.updatePlainTail(combined, nil)
 		return nil, nil, nil
 	}
 	matches = s.assignIDs(matches)
 	cacheMatches := filterMatchesNewBytes(matches, len(tail))
-	trimmed := trimMatches(matches, len(tail), len(combined))
+	trimmed := trimMatches(withBlock(matches, block), len(tail), len(combined))
 	var matchesBySeg map[int][]Match
 	if len(trimmed) > 0 {
 		matchesBySeg = splitMatchesBySegment(trimmed, infos)
 	}
-	s.updatePlainTail(combined)
+	s.updatePlainTail(combined, matches)
 	return matchesBySeg, cacheMatches, combined
 }
 
-func (s *Stream) updatePlainTail(plain []byte) {
+// keyBlockEnd matches the END line of an armored private key.
+var keyBlockEnd = regexp.MustCompile(`-----END (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----`)
+
+// findOpenBlock returns the span of text, from the bytes after from or after
+// a key block the detector cut short, that belongs to a private key block
+// still open, and whether its END line is in text. It returns nil when no
+// block is open.
+func (s *Stream) findOpenBlock(text []byte, from int, matches []Match) (*Match, bool) {
+	var block *Match
+	if s.openBlock != nil {
+		open := *s.openBlock
+		open.Start = from
+		block = &open
+	}
+	for _, m := range matches {
+		if m.End >= from && m.End < len(text) && cutKeyBlock(text[m.Start:m.End]) {
+			cut := m
+			cut.Start = m.End
+			block = &cut
+		}
+	}
+	if block == nil {
+		return nil, false
+	}
+	block.End = len(text)
+	// An END line may have started in bytes already written.
+	lineStart := bytes.LastIndexByte(text[:block.Start], '\n') + 1
+	for _, loc := range keyBlockEnd.FindAllIndex(text[lineStart:], -1) {
+		if lineStart+loc[1] > block.Start {
+			block.End = lineStart + loc[1]
+			return block, true
+		}
+	}
+	return block, false
+}
+
+// cutKeyBlock reports whether a match is a key block with no END line, which
+// the detector ends at redaction.block_max_bytes.
+func cutKeyBlock(match []byte) bool {
+	return bytes.HasPrefix(match, []byte("-----BEGIN ")) && !bytes.Contains(match, []byte("-----END "))
+}
+
+// setOpenBlock records whether block is still open after this write.
+func (s *Stream) setOpenBlock(block *Match, closed bool) {
+	if block == nil {
+		return
+	}
+	if closed {
+		s.openBlock = nil
+		return
+	}
+	open := *block
+	s.openBlock = &open
+}
+
+// withBlock returns matches with block added and the matches it covers
+// removed.
+func withBlock(matches []Match, block *Match) []Match {
+	if block == nil || block.End <= block.Start {
+		return matches
+	}
+	out := make([]Match, 0, len(matches)+1)
+	for _, m := range matches {
+		if m.End <= block.Start || m.Start >= block.End {
+			out = append(out, m)
+		}
+	}
+	return append(out, *block)
+}
+
+// updatePlainTail keeps the last plainTailN bytes as context for the next
+// write. A match that runs to the end of plain, such as a private key block
+// whose END line has not arrived, is kept whole so the detector still sees
+// where it began.
+func (s *Stream) updatePlainTail(plain []byte, matches []Match) {
 	if s.plainTailN <= 0 {
 		s.plainTail = nil
 		return
 	}
-	if len(plain) <= s.plainTailN {
+	keep := len(plain) - s.plainTailN
+	for _, m := range matches {
+		if m.End == len(plain) && m.Start < keep {
+			keep = m.Start
+		}
+	}
+	if keep <= 0 {
 		s.plainTail = append([]byte(nil), plain...)
 		return
 	}
-	s.plainTail = append([]byte(nil), plain[len(plain)-s.plainTailN:]...)
+	s.plainTail = append([]byte(nil), plain[keep:]...)
 }
 
 func trimMatches(matches []Match, trim int, total int) []Match {
@@ -441,6 +795,68 @@ func filterMatches(matches []Match, emitLen int) []Match {
 	return out
 }
 
+// observeEscape tracks state carried by an escape sequence and returns the
+// bytes to write in its place.
+func (s *Stream) observeEscape(esc []byte) []byte {
+	s.updateAltScreen(esc)
+	if host, dir, ok := ansi.WorkingDirectory(esc); ok {
+		s.reportedHost, s.reportedDir = host, dir
+	}
+	if mark, ok := ansi.ParseSemanticMark(esc); ok {
+		if s.markToken != "" && subtle.ConstantTimeCompare([]byte(mark.Token), []byte(s.markToken)) == 1 {
+			s.updateCommand(mark)
+		}
+		return ansi.StripMarkParams(esc)
+	}
+	return esc
+}
+
+// updateCommand follows the shell from prompt to command and back. Output
+// between a command start and the next command end or prompt belongs to that
+// command.
+func (s *Stream) updateCommand(mark ansi.SemanticMark) {
+	switch mark.Kind {
+	case ansi.MarkCommandStart:
+		s.openBlock = nil
+		s.command = mark.Command
+		s.applyCommandSettings()
+		if s.logger != nil && mark.Command != "" {
+			s.logger.Infof("command start settings=%t bypass=%t", s.commandActive, s.commandBypassed)
+		}
+	case ansi.MarkCommandEnd, ansi.MarkPromptStart:
+		if s.logger != nil && s.command != "" && mark.HasExitCode {
+			s.logger.Infof("command end exit=%d", mark.ExitCode)
+		}
+		s.openBlock = nil
+		s.command = ""
+		s.applyCommandSettings()
+	}
+}
+
+// applyCommandSettings switches to the current command's settings, or back
+// to the session's when there is no command or it has none.
+func (s *Stream) applyCommandSettings() {
+	var settings *CommandSettings
+	if s.command != "" && s.commandSettings != nil {
+		settings = s.commandSettings(s.command)
+	}
+	if settings == nil {
+		if s.commandActive {
+			s.applyConfig(s.cfg, s.sessionDetector)
+			s.commandActive = false
+		}
+		s.commandBypassed, s.commandPauseBlocked = false, false
+		return
+	}
+	detector := settings.Detector
+	if detector == nil {
+		detector = s.sessionDetector
+	}
+	s.applyConfig(settings.Config, detector)
+	s.commandActive = true
+	s.commandBypassed, s.commandPauseBlocked = settings.Bypass, settings.PauseBlocked
+}
+
 func (s *Stream) updateAltScreen(esc []byte) {
 	if bytes.Contains(esc, []byte("[?1049h")) || bytes.Contains(esc, []byte("[?47h")) || bytes.Contains(esc, []byte("[?1047h")) {
 		s.altScreen = true
@@ -476,6 +892,9 @@ func (s *Stream) storeMatches(text []byte, matches []Match) {
 		if m.Start < 0 || m.End > len(text) || m.End <= m.Start {
 			continue
 		}
+		if s.redactor.actionFor(m) == types.ActionWarn {
+			continue
+		}
 		label := extractLabel(text, m)
 		s.cache.Put(cache.SecretRecord{
 			ID:       m.ID,
@@ -492,7 +911,12 @@ func (s *Stream) logMatches(matches []Match) {
 		return
 	}
 	for _, m := range matches {
-		s.logger.Infof("redact event id=%d type=%s rule=%s action=%s", m.ID, m.SecretType, m.RuleName, m.Action)
+		action := s.redactor.actionFor(m)
+		if action == types.ActionWarn {
+			s.logger.Infof("warn event id=%d type=%s rule=%s", m.ID, m.SecretType, m.RuleName)
+			continue
+		}
+		s.logger.Infof("redact event id=%d type=%s rule=%s action=%s", m.ID, m.SecretType, m.RuleName, action)
 	}
 }
 
@@ -507,16 +931,39 @@ func (s *Stream) maybeEmitStatus(matches []Match, redacted []byte) {
 		return
 	}
 	first := matches[0]
-	line := ui.StatusLine(len(matches), s.strictMode, s.includeID, first.SecretType, first.ID)
+	var line string
+	if s.allWarn(matches) {
+		line = ui.WarnStatusLine(len(matches), s.includeID, first.SecretType, first.ID)
+	} else {
+		line = ui.StatusLine(len(matches), s.strictMode, s.includeID, first.SecretType, first.ID)
+	}
 	if line == "" {
 		return
 	}
-	if _, err := s.out.Write([]byte(line + "\n")); err != nil {
+	if err := s.writeOut([]byte(line + "\n")); err != nil {
 		return
 	}
 	s.lastStatus = time.Now()
 }
 
+func (s *Stream) anyWarn(matches []Match) bool {
+	for _, m := range matches {
+		if s.redactor.actionFor(m) == types.ActionWarn {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Stream) allWarn(matches []Match) bool {
+	for _, m := range matches {
+		if s.redactor.actionFor(m) != types.ActionWarn {
+			return false
+		}
+	}
+	return len(matches) > 0
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
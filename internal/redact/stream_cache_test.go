@@ -8,7 +8,6 @@ import (
 
 	"github.com/suryansh-23/secretty/internal/cache"
 	"github.com/suryansh-23/secretty/internal/config"
-	"github.com/suryansh-23/secretty/internal/detect"
 	"github.com/suryansh-23/secretty/internal/redact"
 )
 
@@ -17,11 +16,11 @@ func TestInteractiveCacheStoresSecretWithTail(t *testing.T) {
 	cfg.Redaction.RollingWindowBytes = 0
 	cfg.Overrides.CopyWithoutRender.Enabled = true
 	cfg.Overrides.CopyWithoutRender.TTLSeconds = 30
-	cfg.Rulesets.Web3.Enabled = true
+	cfg.Rulesets.SetEnabled(config.RulesetWeb3, true)
 
 	out := &bytes.Buffer{}
 	secretCache := cache.New(64, 30*time.Second)
-	stream := redact.NewStream(out, cfg, detect.NewEngine(cfg), secretCache, nil, nil)
+	stream := redact.NewStream(out, cfg, newEngine(t, cfg), secretCache, nil, nil)
 
 	if _, err := stream.Write([]byte("prefix output\n")); err != nil {
 		t.Fatalf("write prefix: %v", err)
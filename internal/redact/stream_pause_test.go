@@ -6,7 +6,6 @@ import (
 	"testing"
 
 	"github.com/suryansh-23/secretty/internal/config"
-	"github.com/suryansh-23/secretty/internal/detect"
 	"github.com/suryansh-23/secretty/internal/redact"
 	"github.com/suryansh-23/secretty/internal/types"
 )
@@ -24,11 +23,11 @@ func TestStreamPausePassThroughAndResume(t *testing.T) {
 	cfg.Redaction.RollingWindowBytes = 0
 	cfg.Masking.Style = types.MaskStyleBlock
 	cfg.Masking.BlockChar = "#"
-	cfg.Rulesets.Web3.Enabled = true
+	cfg.Rulesets.SetEnabled(config.RulesetWeb3, true)
 
 	out := &bytes.Buffer{}
 	pause := &testPauseGate{}
-	stream := redact.NewStream(out, cfg, detect.NewEngine(cfg), nil, nil, pause)
+	stream := redact.NewStream(out, cfg, newEngine(t, cfg), nil, nil, pause)
 
 	pause.active = true
 	secret := "PRIVATE_KEY=0x" + strings.Repeat("a", 64) + "\n"
@@ -59,11 +58,11 @@ func TestStreamPauseFlushesPendingBeforeBypass(t *testing.T) {
 	cfg.Redaction.RollingWindowBytes = 64
 	cfg.Masking.Style = types.MaskStyleBlock
 	cfg.Masking.BlockChar = "#"
-	cfg.Rulesets.Web3.Enabled = true
+	cfg.Rulesets.SetEnabled(config.RulesetWeb3, true)
 
 	out := &bytes.Buffer{}
 	pause := &testPauseGate{}
-	stream := redact.NewStream(out, cfg, detect.NewEngine(cfg), nil, nil, pause)
+	stream := redact.NewStream(out, cfg, newEngine(t, cfg), nil, nil, pause)
 
 	secret := "PRIVATE_KEY=0x" + strings.Repeat("a", 64)
 	if _, err := stream.Write([]byte(secret)); err != nil {
@@ -83,3 +82,89 @@ func TestStreamPauseFlushesPendingBeforeBypass(t *testing.T) {
 		t.Fatalf("expected pending buffered secret to flush redacted before pause bypass, got %q", got)
 	}
 }
+
+func TestStreamTranscriptSkipsPausedOutput(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Redaction.RollingWindowBytes = 0
+	cfg.Masking.Style = types.MaskStyleBlock
+	cfg.Masking.BlockChar = "#"
+	cfg.Rulesets.SetEnabled(config.RulesetWeb3, true)
+
+	out := &bytes.Buffer{}
+	transcript := &bytes.Buffer{}
+	pause := &testPauseGate{}
+	stream := redact.NewStream(out, cfg, newEngine(t, cfg), nil, nil, pause)
+	stream.SetTranscript(transcript)
+
+	secret := "PRIVATE_KEY=0x" + strings.Repeat("a", 64) + "\n"
+	if _, err := stream.Write([]byte(secret)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	pause.active = true
+	if _, err := stream.Write([]byte(secret)); err != nil {
+		t.Fatalf("write while paused: %v", err)
+	}
+
+	if !strings.Contains(out.String(), strings.Repeat("a", 16)) {
+		t.Fatalf("expected paused output on screen, got %q", out.String())
+	}
+	if strings.Contains(transcript.String(), strings.Repeat("a", 16)) {
+		t.Fatalf("expected transcript to hold only redacted output, got %q", transcript.String())
+	}
+	if !strings.Contains(transcript.String(), "PRIVATE_KEY=") {
+		t.Fatalf("expected redacted line in transcript, got %q", transcript.String())
+	}
+}
+
+func TestStreamPauseBlockedKeepsRedaction(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Redaction.RollingWindowBytes = 0
+	cfg.Masking.Style = types.MaskStyleBlock
+	cfg.Masking.BlockChar = "#"
+
+	out := &bytes.Buffer{}
+	pause := &testPauseGate{active: true}
+	stream := redact.NewStream(out, cfg, newEngine(t, cfg), nil, nil, pause)
+	stream.SetPauseBlocked(true)
+	secret := "PRIVATE_KEY=0x" + strings.Repeat("a", 64) + "\n"
+	if _, err := stream.Write([]byte(secret)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if strings.Contains(out.String(), strings.Repeat("a", 16)) {
+		t.Fatalf("expected redaction while pause is blocked, got %q", out.String())
+	}
+
+	stream.SetPauseBlocked(false)
+	out.Reset()
+	if _, err := stream.Write([]byte(secret)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if !strings.Contains(out.String(), strings.Repeat("a", 64)) {
+		t.Fatalf("expected the pause to apply once unblocked, got %q", out.String())
+	}
+}
+
+func TestStreamFlushKeepsUnterminatedEscapeOutOfTranscript(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Redaction.RollingWindowBytes = 0
+
+	out := &bytes.Buffer{}
+	transcript := &bytes.Buffer{}
+	pause := &testPauseGate{active: true}
+	stream := redact.NewStream(out, cfg, newEngine(t, cfg), nil, nil, pause)
+	stream.SetTranscript(transcript)
+
+	secret := "PRIVATE_KEY=0x" + strings.Repeat("a", 64)
+	if _, err := stream.Write([]byte("\x1b]0;" + secret)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if !strings.Contains(out.String(), secret) {
+		t.Fatalf("expected leftover escape on screen, got %q", out.String())
+	}
+	if transcript.Len() != 0 {
+		t.Fatalf("expected nothing in transcript, got %q", transcript.String())
+	}
+}
@@ -0,0 +1,73 @@
+package redact_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/config"
+	"github.com/suryansh-23/secretty/internal/redact"
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+func TestStreamReconfigureSwapsSettings(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Redaction.RollingWindowBytes = 0
+	cfg.Redaction.StatusLine.Enabled = false
+	cfg.Masking.Style = types.MaskStyleBlock
+	cfg.Masking.BlockChar = "#"
+
+	out := &bytes.Buffer{}
+	stream := redact.NewStream(out, cfg, redact.NoopDetector{}, nil, nil, nil)
+	secret := "PRIVATE_KEY=0x" + strings.Repeat("b", 64) + "\n"
+	if _, err := stream.Write([]byte(secret)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if !strings.Contains(out.String(), strings.Repeat("b", 64)) {
+		t.Fatalf("expected no redaction before reload, got %q", out.String())
+	}
+
+	stream.Reconfigure(cfg, newEngine(t, cfg), false)
+	out.Reset()
+	if _, err := stream.Write([]byte(secret)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if strings.Contains(out.String(), strings.Repeat("b", 16)) {
+		t.Fatalf("expected redaction after reload, got %q", out.String())
+	}
+
+	stream.Reconfigure(cfg, newEngine(t, cfg), true)
+	out.Reset()
+	if _, err := stream.Write([]byte(secret)); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if out.String() != secret {
+		t.Fatalf("expected passthrough when bypassed, got %q", out.String())
+	}
+}
+
+func TestStreamReportsWorkingDirectoryAfterWrite(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.Redaction.RollingWindowBytes = 0
+	cfg.Redaction.StatusLine.Enabled = false
+
+	out := &bytes.Buffer{}
+	stream := redact.NewStream(out, cfg, redact.NoopDetector{}, nil, nil, nil)
+	var dirs []string
+	stream.SetDirObserver(func(host, dir string) {
+		// Reconfigure takes the stream lock, so the observer must run
+		// after Write releases it.
+		stream.Reconfigure(cfg, newEngine(t, cfg), false)
+		dirs = append(dirs, host+":"+dir)
+	})
+	osc := "\x1b]7;file://devbox/work/crypto\x07"
+	if _, err := stream.Write([]byte("$ cd crypto\r\n" + osc + "$ ")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if len(dirs) != 1 || dirs[0] != "devbox:/work/crypto" {
+		t.Fatalf("dirs = %q", dirs)
+	}
+	if !strings.Contains(out.String(), osc) {
+		t.Fatalf("expected OSC 7 to pass through, got %q", out.String())
+	}
+}
@@ -0,0 +1,45 @@
+package redact_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/config"
+	"github.com/suryansh-23/secretty/internal/redact"
+)
+
+func TestStreamMasksStructuredValuesInPlace(t *testing.T) {
+	input := "$ kubectl get secret db -o yaml\n" +
+		"data:\n" +
+		"  password: zYxWvUtS==\n" +
+		"  ca.crt: LS0tLS1CRUdJTg==\n" +
+		"stringData:\n" +
+		"  private_key: |\n" +
+		"    zig-zug-rim\n" +
+		"    wrong-turn\n" +
+		"kind: Secret\n" +
+		"$ terraform output -json\n" +
+		"{\n  \"db_password\": {\n    \"sensitive\": true,\n    \"value\": \"zqy-vwx-trs\"\n  }\n}\n"
+	// Secrets avoid hex digits: the block style masks an all-hex fragment,
+	// such as the tail of a value split across writes, with random hex.
+	want := input
+	for _, secret := range []string{"zYxWvUtS==", "zig-zug-rim", "wrong-turn", "zqy-vwx-trs"} {
+		want = strings.Replace(want, secret, strings.Repeat("#", len(secret)), 1)
+	}
+
+	for _, window := range []int{0, 32768} {
+		cfg := blockConfig(window)
+		cfg.Rulesets.SetEnabled(config.RulesetStructured, true)
+		out := &bytes.Buffer{}
+		stream := redact.NewStream(out, cfg, newEngine(t, cfg), nil, nil, nil)
+		var parts []string
+		for i := 0; i < len(input); i += 7 {
+			parts = append(parts, input[i:min(i+7, len(input))])
+		}
+		writeAll(t, stream, parts...)
+		if out.String() != want {
+			t.Fatalf("window %d:\ngot  %q\nwant %q", window, out.String(), want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package scrub
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/suryansh-23/secretty/internal/config"
+	"github.com/suryansh-23/secretty/internal/debug"
+	"github.com/suryansh-23/secretty/internal/redact"
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+// Format identifies the kind of input being scrubbed.
+type Format string
+
+const (
+	FormatAuto      Format = "auto"
+	FormatText      Format = "text"
+	FormatAsciicast Format = "asciicast"
+)
+
+const (
+	defaultWindowBytes = 32768
+	// castWindowBytes bounds how much of a partial line an event can hold
+	// back for the next one, to catch secrets split across them.
+	castWindowBytes = 4096
+	maxCastLineSize = 16 * 1024 * 1024
+)
+
+// Options configures an offline scrub.
+type Options struct {
+	Config   config.Config
+	Detector redact.Detector
+	Logger   *debug.Logger
+}
+
+// Run scrubs src into dst. FormatAuto sniffs for an asciicast v2 header.
+func Run(dst io.Writer, src io.Reader, format Format, opts Options) error {
+	br := bufio.NewReaderSize(src, 64*1024)
+	if format == "" || format == FormatAuto {
+		format = Sniff(br)
+	}
+	switch format {
+	case FormatText:
+		return Text(dst, br, opts)
+	case FormatAsciicast:
+		return Asciicast(dst, br, opts)
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// Sniff peeks at the first line to tell asciicast v2 apart from text.
+func Sniff(br *bufio.Reader) Format {
+	peek, _ := br.Peek(4096)
+	line := peek
+	if idx := bytes.IndexByte(peek, '\n'); idx >= 0 {
+		line = peek[:idx]
+	}
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(line, &header); err == nil && header.Version == 2 {
+		return FormatAsciicast
+	}
+	return FormatText
+}
+
+// Text scrubs plain text or raw terminal captures. Escape sequences pass
+// through untouched.
+func Text(dst io.Writer, src io.Reader, opts Options) error {
+	stream := newStream(dst, opts, defaultWindowBytes)
+	if _, err := io.Copy(stream, src); err != nil {
+		return err
+	}
+	return stream.Close()
+}
+
+// Asciicast scrubs an asciicast v2 recording. Only output ("o") event
+// payloads are redacted; the header and other events are copied verbatim.
+// Each event keeps its own redacted output and timestamp; only a trailing
+// partial line, which a later event could extend into a secret, moves to the
+// next output event.
+func Asciicast(dst io.Writer, src io.Reader, opts Options) error {
+	br, ok := src.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(src)
+	}
+	w := bufio.NewWriter(dst)
+	var pending bytes.Buffer
+	stream := newStream(&pending, opts, castWindowBytes)
+
+	var lastTime json.RawMessage
+	emit := func(ts json.RawMessage) error {
+		if pending.Len() == 0 {
+			return nil
+		}
+		data := pending.String()
+		pending.Reset()
+		return writeEvent(w, ts, "o", data)
+	}
+
+	lineNo := 0
+	for {
+		line, err := readLine(br)
+		if len(line) > 0 {
+			lineNo++
+			if lineNo == 1 {
+				if _, werr := w.Write(append(line, '\n')); werr != nil {
+					return werr
+				}
+			} else if len(bytes.TrimSpace(line)) > 0 {
+				var event []json.RawMessage
+				if jerr := json.Unmarshal(line, &event); jerr != nil || len(event) < 3 {
+					return fmt.Errorf("asciicast line %d: invalid event", lineNo)
+				}
+				lastTime = event[0]
+				var kind string
+				if jerr := json.Unmarshal(event[1], &kind); jerr != nil {
+					return fmt.Errorf("asciicast line %d: invalid event type", lineNo)
+				}
+				if kind == "o" {
+					var data string
+					if jerr := json.Unmarshal(event[2], &data); jerr != nil {
+						return fmt.Errorf("asciicast line %d: invalid output data", lineNo)
+					}
+					if _, werr := stream.Write([]byte(data)); werr != nil {
+						return werr
+					}
+					if werr := stream.Release(); werr != nil {
+						return werr
+					}
+					if werr := emit(event[0]); werr != nil {
+						return werr
+					}
+				} else if _, werr := w.Write(append(line, '\n')); werr != nil {
+					return werr
+				}
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+	}
+	if err := stream.Close(); err != nil {
+		return err
+	}
+	if lastTime == nil {
+		lastTime = json.RawMessage("0")
+	}
+	if err := emit(lastTime); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func newStream(out io.Writer, opts Options, window int) *redact.Stream {
+	cfg := opts.Config
+	cfg.Redaction.StatusLine.Enabled = false
+	// Scrubbed output is meant to be shared, so secrets are never only
+	// highlighted: strict no_reveal masks what warn mode or a warn action
+	// would leave in place.
+	cfg.Mode = types.ModeStrict
+	cfg.Strict.NoReveal = true
+	if cfg.Redaction.RollingWindowBytes <= 0 || cfg.Redaction.RollingWindowBytes > window {
+		cfg.Redaction.RollingWindowBytes = window
+	}
+	return redact.NewStream(out, cfg, opts.Detector, nil, opts.Logger, nil)
+}
+
+func writeEvent(w io.Writer, ts json.RawMessage, kind, data string) error {
+	encoded, err := json.Marshal([]any{ts, kind, data})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(encoded, '\n'))
+	return err
+}
+
+func readLine(br *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := br.ReadLine()
+		line = append(line, chunk...)
+		if len(line) > maxCastLineSize {
+			return nil, errors.New("asciicast line too long")
+		}
+		if err != nil || !isPrefix {
+			return line, err
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package scrub
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/suryansh-23/secretty/internal/config"
+	"github.com/suryansh-23/secretty/internal/detect"
+	"github.com/suryansh-23/secretty/internal/types"
+)
+
+const testKey = "0x4c0883a69102937d6231471b5dbb6204fe5129617082791b2b2f2c0f4b1c2d3e"
+
+func testOptions(t *testing.T) Options {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.Redaction.DefaultAction = types.ActionPlaceholder
+	for i := range cfg.Rules {
+		cfg.Rules[i].Action = types.ActionPlaceholder
+	}
+	for i := range cfg.TypedDetectors {
+		cfg.TypedDetectors[i].Action = types.ActionPlaceholder
+	}
+	engine, err := detect.NewEngine(cfg)
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	return Options{Config: cfg, Detector: engine}
+}
+
+func TestTextRedactsAndKeepsANSI(t *testing.T) {
+	input := "\x1b[32mPRIVATE_KEY=" + testKey + "\x1b[0m\n"
+	var out bytes.Buffer
+	if err := Run(&out, strings.NewReader(input), FormatAuto, testOptions(t)); err != nil {
+		t.Fatalf("scrub: %v", err)
+	}
+	want := "\x1b[32mPRIVATE_KEY=⟦REDACTED:EVM_PK⟧\x1b[0m\n"
+	if got := out.String(); got != want {
+		t.Fatalf("scrub = %q, want %q", got, want)
+	}
+}
+
+func TestTextKeepsEscapesInOrder(t *testing.T) {
+	input := "hello \x1b[31mred\x1b[0m world\n"
+	var out bytes.Buffer
+	if err := Run(&out, strings.NewReader(input), FormatText, testOptions(t)); err != nil {
+		t.Fatalf("scrub: %v", err)
+	}
+	if got := out.String(); got != input {
+		t.Fatalf("scrub = %q, want %q", got, input)
+	}
+}
+
+func TestTextMasksInWarnMode(t *testing.T) {
+	opts := testOptions(t)
+	opts.Config.Mode = types.ModeWarn
+	opts.Config.Strict.NoReveal = false
+	var out bytes.Buffer
+	if err := Run(&out, strings.NewReader("PRIVATE_KEY="+testKey+"\n"), FormatText, opts); err != nil {
+		t.Fatalf("scrub: %v", err)
+	}
+	if strings.Contains(out.String(), testKey[2:]) {
+		t.Fatalf("warn mode left the secret in scrubbed output: %q", out.String())
+	}
+}
+
+func TestSniffDetectsAsciicast(t *testing.T) {
+	header := `{"version": 2, "width": 80, "height": 24}` + "\n"
+	if got := Sniff(bufio.NewReader(strings.NewReader(header))); got != FormatAsciicast {
+		t.Fatalf("expected asciicast, got %s", got)
+	}
+	if got := Sniff(bufio.NewReader(strings.NewReader("plain log line\n"))); got != FormatText {
+		t.Fatalf("expected text, got %s", got)
+	}
+}
+
+func TestAsciicastRedactsSplitOutputOnly(t *testing.T) {
+	half := len(testKey) / 2
+	lines := []string{
+		`{"version": 2, "width": 80, "height": 24}`,
+		eventLine(t, 0.1, "o", "PRIVATE_KEY="+testKey[:half]),
+		eventLine(t, 0.2, "i", "ls\r"),
+		eventLine(t, 0.3, "o", testKey[half:]+"\r\n"),
+		eventLine(t, 0.4, "r", "100x30"),
+	}
+	input := strings.Join(lines, "\n") + "\n"
+
+	var out bytes.Buffer
+	if err := Run(&out, strings.NewReader(input), FormatAuto, testOptions(t)); err != nil {
+		t.Fatalf("scrub: %v", err)
+	}
+	outLines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if outLines[0] != lines[0] {
+		t.Fatalf("header changed: %q", outLines[0])
+	}
+
+	var output strings.Builder
+	kinds := map[string]int{}
+	for _, line := range outLines[1:] {
+		var event []any
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("invalid event %q: %v", line, err)
+		}
+		kind := event[1].(string)
+		kinds[kind]++
+		if kind == "o" {
+			output.WriteString(event[2].(string))
+		}
+	}
+	if kinds["i"] != 1 || kinds["r"] != 1 {
+		t.Fatalf("non-output events not preserved: %v", kinds)
+	}
+	got := output.String()
+	if strings.Contains(got, testKey[:half]) || strings.Contains(got, testKey[half:]) {
+		t.Fatalf("secret leaked across events: %q", got)
+	}
+	if !strings.Contains(got, "PRIVATE_KEY=⟦REDACTED:EVM_PK⟧") {
+		t.Fatalf("expected placeholder, got %q", got)
+	}
+}
+
+func TestAsciicastKeepsEventsAndTimestamps(t *testing.T) {
+	lines := []string{
+		`{"version": 2, "width": 80, "height": 24}`,
+		eventLine(t, 0.1, "o", "$ ls\r\n"),
+		eventLine(t, 0.2, "o", "\x1b[1mbold\x1b[0m plain\r\n"),
+		eventLine(t, 0.5, "o", "PRIVATE_KEY="+testKey+"\r\n"),
+	}
+	var out bytes.Buffer
+	if err := Run(&out, strings.NewReader(strings.Join(lines, "\n")+"\n"), FormatAsciicast, testOptions(t)); err != nil {
+		t.Fatalf("scrub: %v", err)
+	}
+	want := []string{
+		lines[0],
+		lines[1],
+		lines[2],
+		eventLine(t, 0.5, "o", "PRIVATE_KEY=⟦REDACTED:EVM_PK⟧\r\n"),
+	}
+	if got := strings.Split(strings.TrimRight(out.String(), "\n"), "\n"); !slices.Equal(got, want) {
+		t.Fatalf("events = %q, want %q", got, want)
+	}
+}
+
+func TestAsciicastRejectsInvalidEvent(t *testing.T) {
+	input := `{"version": 2, "width": 80, "height": 24}` + "\nnot json\n"
+	var out bytes.Buffer
+	err := Run(&out, strings.NewReader(input), FormatAsciicast, testOptions(t))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected line 2 error, got %v", err)
+	}
+}
+
+func eventLine(t *testing.T, ts float64, kind, data string) string {
+	t.Helper()
+	encoded, err := json.Marshal([]any{ts, kind, data})
+	if err != nil {
+		t.Fatalf("marshal event: %v", err)
+	}
+	return string(encoded)
+}
@@ -124,6 +124,16 @@ func blockForShell(kind, configPath, binPath string) ([]string, error) {
 			"  elif [[ -n \"$SECRETTY_HOOK_DEBUG\" ]]; then",
 			"    echo \"secretty hook: shell=zsh interactive=$- wrapped=$SECRETTY_WRAPPED tty_ok=0\" >&2",
 			"  fi",
+			"elif [[ -o interactive ]]; then",
+			"  secretty_report_cwd() { printf '\\033]7;file://%s%s\\007' \"$HOST\" \"$PWD\"; }",
+			"  secretty_token=\"$SECRETTY_MARK_TOKEN\"",
+			"  unset SECRETTY_MARK_TOKEN",
+			"  secretty_preexec() { printf '\\033]133;C;secretty=%s;cmdline=%s\\007' \"$secretty_token\" \"${1//[[:cntrl:]]/ }\"; }",
+			"  secretty_precmd() { printf '\\033]133;D;%s;secretty=%s\\007\\033]133;A;secretty=%s\\007' \"$?\" \"$secretty_token\" \"$secretty_token\"; }",
+			"  autoload -Uz add-zsh-hook",
+			"  add-zsh-hook chpwd secretty_report_cwd",
+			"  add-zsh-hook preexec secretty_preexec",
+			"  add-zsh-hook precmd secretty_precmd",
 			"fi",
 			endMarker,
 		}, nil
@@ -150,6 +160,30 @@ func blockForShell(kind, configPath, binPath string) ([]string, error) {
 			"      elif [ -n \"$SECRETTY_HOOK_DEBUG\" ]; then",
 			"        echo \"secretty hook: shell=bash interactive=$- wrapped=$SECRETTY_WRAPPED tty_ok=0\" >&2",
 			"      fi",
+			"    else",
+			"      secretty_report_cwd() { printf '\\033]7;file://%s%s\\007' \"$HOSTNAME\" \"$PWD\"; }",
+			"      secretty_token=\"$SECRETTY_MARK_TOKEN\"",
+			"      unset SECRETTY_MARK_TOKEN",
+			"      secretty_precmd() {",
+			"        secretty_status=$?",
+			"        printf '\\033]133;D;%s;secretty=%s\\007\\033]133;A;secretty=%s\\007' \"$secretty_status\" \"$secretty_token\" \"$secretty_token\"",
+			"        secretty_report_cwd",
+			"        secretty_at_prompt=1",
+			"        return $secretty_status",
+			"      }",
+			"      secretty_preexec() {",
+			"        [ -n \"$secretty_at_prompt\" ] && [ -z \"$COMP_LINE\" ] || return 0",
+			"        case \"$PROMPT_COMMAND\" in *\"$BASH_COMMAND\"*) return 0 ;; esac",
+			"        secretty_at_prompt=",
+			"        printf '\\033]133;C;secretty=%s;cmdline=%s\\007' \"$secretty_token\" \"${BASH_COMMAND//[[:cntrl:]]/ }\"",
+			"      }",
+			"      case \";${PROMPT_COMMAND:-};\" in",
+			"        *\";secretty_precmd;\"*) ;;",
+			"        *) PROMPT_COMMAND=\"secretty_precmd${PROMPT_COMMAND:+;$PROMPT_COMMAND}\" ;;",
+			"      esac",
+			"      if [ -n \"$BASH_VERSION\" ] && [ -z \"$(trap -p DEBUG)\" ]; then",
+			"        trap 'secretty_preexec' DEBUG",
+			"      fi",
 			"    fi",
 			"    ;;",
 			"esac",
@@ -176,6 +210,21 @@ func blockForShell(kind, configPath, binPath string) ([]string, error) {
 			"  else if set -q SECRETTY_HOOK_DEBUG",
 			"    echo \"secretty hook: shell=fish wrapped=$SECRETTY_WRAPPED tty_ok=0\" >&2",
 			"  end",
+			"else if status --is-interactive",
+			"  function secretty_report_cwd --on-variable PWD",
+			"    printf '\\033]7;file://%s%s\\007' (hostname) $PWD",
+			"  end",
+			"  set -g secretty_token \"$SECRETTY_MARK_TOKEN\"",
+			"  set -e SECRETTY_MARK_TOKEN",
+			"  function secretty_preexec --on-event fish_preexec",
+			"    printf '\\033]133;C;secretty=%s;cmdline=%s\\007' $secretty_token (string replace -ra '[[:cntrl:]]' ' ' -- $argv)",
+			"  end",
+			"  function secretty_postexec --on-event fish_postexec",
+			"    printf '\\033]133;D;%s;secretty=%s\\007' $status $secretty_token",
+			"  end",
+			"  function secretty_prompt --on-event fish_prompt",
+			"    printf '\\033]133;A;secretty=%s\\007' $secretty_token",
+			"  end",
 			"end",
 			endMarker,
 		}, nil
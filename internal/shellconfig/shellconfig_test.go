@@ -66,3 +66,30 @@ func TestInstallBlock(t *testing.T) {
 		t.Fatalf("expected tty redirection")
 	}
 }
+
+func TestBlocksReportWorkingDirectoryWhenWrapped(t *testing.T) {
+	for _, kind := range []string{"zsh", "bash", "fish"} {
+		block, err := blockForShell(kind, "/tmp/secretty/config.yaml", "/usr/local/bin/secretty")
+		if err != nil {
+			t.Fatalf("%s: %v", kind, err)
+		}
+		if !strings.Contains(strings.Join(block, "\n"), `\033]7;file://%s%s\007`) {
+			t.Fatalf("%s: expected OSC 7 working directory report", kind)
+		}
+	}
+}
+
+func TestBlocksEmitSemanticPromptMarksWhenWrapped(t *testing.T) {
+	for _, kind := range []string{"zsh", "bash", "fish"} {
+		block, err := blockForShell(kind, "/tmp/secretty/config.yaml", "/usr/local/bin/secretty")
+		if err != nil {
+			t.Fatalf("%s: %v", kind, err)
+		}
+		text := strings.Join(block, "\n")
+		for _, mark := range []string{`\033]133;A;secretty=%s\007`, `\033]133;C;secretty=%s;cmdline=%s\007`, `\033]133;D;%s;secretty=%s\007`, "SECRETTY_MARK_TOKEN"} {
+			if !strings.Contains(text, mark) {
+				t.Fatalf("%s: expected %s in the block", kind, mark)
+			}
+		}
+	}
+}
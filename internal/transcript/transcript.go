@@ -0,0 +1,114 @@
+package transcript
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/suryansh-23/secretty/internal/ansi"
+)
+
+// DefaultMaxBytes bounds the ring when no size is configured.
+const DefaultMaxBytes = 256 * 1024
+
+// Ring keeps the most recent bytes of sanitized session output in memory.
+// Once full, new output overwrites the oldest in place; the held bytes run
+// from head for n bytes, wrapping at the end of buf.
+type Ring struct {
+	mu       sync.Mutex
+	buf      []byte
+	head     int
+	n        int
+	maxBytes int
+}
+
+// NewRing returns a ring holding at most maxBytes of output.
+func NewRing(maxBytes int) *Ring {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	return &Ring{maxBytes: maxBytes}
+}
+
+// Write appends output, discarding the oldest bytes beyond the bound.
+func (r *Ring) Write(p []byte) (int, error) {
+	if r == nil {
+		return len(p), nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.buf == nil {
+		r.buf = make([]byte, r.maxBytes)
+	}
+	written := len(p)
+	if len(p) > r.maxBytes {
+		p = p[len(p)-r.maxBytes:]
+	}
+	for len(p) > 0 {
+		copied := copy(r.buf[(r.head+r.n)%r.maxBytes:], p)
+		p = p[copied:]
+		r.n += copied
+		if overflow := r.n - r.maxBytes; overflow > 0 {
+			r.head = (r.head + overflow) % r.maxBytes
+			r.n = r.maxBytes
+		}
+	}
+	return written, nil
+}
+
+// Bytes returns a copy of everything currently held.
+func (r *Ring) Bytes() []byte {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]byte, r.n)
+	copied := copy(out, r.buf[r.head:min(r.head+r.n, len(r.buf))])
+	copy(out[copied:], r.buf)
+	return out
+}
+
+// LastBytes returns a copy of the most recent n bytes.
+func (r *Ring) LastBytes(n int) []byte {
+	data := r.Bytes()
+	if n <= 0 || n >= len(data) {
+		return data
+	}
+	return data[len(data)-n:]
+}
+
+// LastLines returns a copy of the most recent n lines. A trailing partial
+// line counts as a line.
+func (r *Ring) LastLines(n int) []byte {
+	data := r.Bytes()
+	if n <= 0 {
+		return data
+	}
+	end := len(data)
+	if end > 0 && data[end-1] == '\n' {
+		end--
+	}
+	for i := 0; i < n; i++ {
+		idx := bytes.LastIndexByte(data[:end], '\n')
+		if idx < 0 {
+			return data
+		}
+		end = idx
+	}
+	return data[end+1:]
+}
+
+// StripANSI removes escape sequences using the streaming tokenizer and
+// normalizes CRLF line endings so the result pastes cleanly as plain text.
+func StripANSI(data []byte) []byte {
+	tok := &ansi.Tokenizer{}
+	segments := append(tok.Push(data), tok.Flush()...)
+	var out bytes.Buffer
+	for _, seg := range segments {
+		if seg.Kind != ansi.SegmentText {
+			continue
+		}
+		out.Write(seg.Bytes)
+	}
+	return bytes.ReplaceAll(out.Bytes(), []byte("\r\n"), []byte("\n"))
+}
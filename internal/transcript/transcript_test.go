@@ -0,0 +1,66 @@
+package transcript
+
+import "testing"
+
+func TestRingKeepsMostRecentBytes(t *testing.T) {
+	r := NewRing(8)
+	if _, err := r.Write([]byte("abcdef")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := r.Write([]byte("ghij")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got := string(r.Bytes()); got != "cdefghij" {
+		t.Fatalf("bytes = %q", got)
+	}
+	if _, err := r.Write([]byte("0123456789")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got := string(r.Bytes()); got != "23456789" {
+		t.Fatalf("bytes after oversized write = %q", got)
+	}
+	if got := string(r.LastBytes(3)); got != "789" {
+		t.Fatalf("last bytes = %q", got)
+	}
+}
+
+func TestRingWrapsAcrossWrites(t *testing.T) {
+	r := NewRing(5)
+	var all string
+	for _, part := range []string{"ab", "cde", "f", "ghij", "k", "lmnop", "qr"} {
+		if _, err := r.Write([]byte(part)); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		all += part
+		want := all[max(0, len(all)-5):]
+		if got := string(r.Bytes()); got != want {
+			t.Fatalf("after %q: bytes = %q, want %q", part, got, want)
+		}
+	}
+}
+
+func TestRingLastLines(t *testing.T) {
+	r := NewRing(0)
+	if _, err := r.Write([]byte("one\ntwo\nthree\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got := string(r.LastLines(2)); got != "two\nthree\n" {
+		t.Fatalf("last 2 lines = %q", got)
+	}
+	if got := string(r.LastLines(10)); got != "one\ntwo\nthree\n" {
+		t.Fatalf("last 10 lines = %q", got)
+	}
+	if _, err := r.Write([]byte("fou")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if got := string(r.LastLines(1)); got != "fou" {
+		t.Fatalf("partial last line = %q", got)
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	in := []byte("\x1b[31mred\x1b[0m text\r\n\x1b]0;title\x07next\r\n")
+	if got := string(StripANSI(in)); got != "red text\nnext\n" {
+		t.Fatalf("stripped = %q", got)
+	}
+}
@@ -27,16 +27,31 @@ const (
 	MaskStyleMorse MaskStyle = "morse"
 )
 
+// WarnHighlight controls how warn-mode spans are marked.
+type WarnHighlight string
+
+const (
+	WarnHighlightReverse   WarnHighlight = "reverse"
+	WarnHighlightUnderline WarnHighlight = "underline"
+	WarnHighlightBold      WarnHighlight = "bold"
+)
+
 // SecretType labels a detected secret.
 type SecretType string
 
 const (
 	SecretEvmPrivateKey SecretType = "EVM_PK"
+	SecretSeedPhrase    SecretType = "SEED_PHRASE"
+	SecretExtendedKey   SecretType = "XPRV"
+	SecretWIFKey        SecretType = "WIF"
+	SecretPrivateKey    SecretType = "PRIVATE_KEY"
 	SecretAPIKey        SecretType = "API_KEY"
 	SecretAuthToken     SecretType = "AUTH_TOKEN"
 	SecretJWT           SecretType = "JWT"
 	SecretPassword      SecretType = "PASSWORD"
 	SecretCloudCred     SecretType = "CLOUD_CRED"
+	SecretGeneric       SecretType = "GENERIC_SECRET"
+	SecretKnown         SecretType = "KNOWN_SECRET"
 	SecretUnknown       SecretType = "UNKNOWN"
 )
 
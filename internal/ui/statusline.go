@@ -23,3 +23,18 @@ func StatusLine(count int, strict bool, includeID bool, secretType types.SecretT
 	}
 	return fmt.Sprintf("%s redacted %s", prefix, secretType)
 }
+
+// WarnStatusLine formats a status line for warn-mode detections.
+func WarnStatusLine(count int, includeID bool, secretType types.SecretType, id int) string {
+	if count <= 0 {
+		return ""
+	}
+	prefix := "secretty(warn):"
+	if count > 1 {
+		return fmt.Sprintf("%s detected %d secrets", prefix, count)
+	}
+	if includeID && id > 0 {
+		return fmt.Sprintf("%s detected %s#%d", prefix, secretType, id)
+	}
+	return fmt.Sprintf("%s detected %s", prefix, secretType)
+}
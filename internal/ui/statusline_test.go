@@ -26,3 +26,12 @@ func TestStatusLineMultiple(t *testing.T) {
 		t.Fatalf("line = %q", line)
 	}
 }
+
+func TestWarnStatusLine(t *testing.T) {
+	if line := WarnStatusLine(1, false, types.SecretAPIKey, 0); line != "secretty(warn): detected API_KEY" {
+		t.Fatalf("line = %q", line)
+	}
+	if line := WarnStatusLine(2, false, types.SecretAPIKey, 0); line != "secretty(warn): detected 2 secrets" {
+		t.Fatalf("line = %q", line)
+	}
+}